@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/cokeBeer/goot/pkg/example/dataflow/availableexpression"
+)
+
+const src = `package main
+
+func Hello(a int, b int, c bool) int {
+	x := a + b
+	if c {
+		x = b + a
+	} else {
+		x = a * b
+	}
+	y := a + b
+	return x + y
+}`
+
+func main() {
+	runner := availableexpression.NewRunner(src, "Hello")
+	runner.Run()
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+
 	"github.com/cokeBeer/goot/pkg/example/dataflow/constantpropagation"
 )
 
@@ -21,5 +23,10 @@ func Hello(a int, b int) bool {
 
 func main() {
 	runner := constantpropagation.NewRunner(src, "Hello")
-	runner.Run()
+	//runner.Sparse = true
+	//runner.Format = "annotated"
+	err := runner.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
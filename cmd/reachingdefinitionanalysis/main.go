@@ -0,0 +1,43 @@
+package main
+
+import (
+	"log"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/reachingdefinition"
+)
+
+const src = `package main
+
+type User struct {
+	Name string
+	Age  int
+}
+
+var Count int
+
+func Hello(admin bool, n int) (string, int) {
+	u := &User{}
+	u.Name = "guest"
+	if admin {
+		u.Name = "admin"
+	}
+	arr := [3]int{}
+	arr[1] = 5
+	arr[n] = 6
+	Count = arr[1]
+	update(u)
+	return u.Name, arr[2] + Count
+}
+
+func update(u *User) {
+	u.Age = 18
+}`
+
+func main() {
+	runner := reachingdefinition.NewRunner(src, "Hello")
+	//runner.DstPath = "chains.json"
+	err := runner.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
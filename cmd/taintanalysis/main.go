@@ -15,6 +15,8 @@ func main() {
 	//runner.PassThroughSrcPath = []string{"gostd1.19.json", "additional.json"}
 	runner.PassThroughDstPath = "passthrough.json"
 	runner.TaintGraphDstPath = "taintgraph.json"
+	//runner.FindingDstPath = "findings.json"
+	//runner.SarifDstPath = "findings.sarif"
 	runner.UsePointerAnalysis = false
 	runner.PassThroughOnly = true
 	runner.InitOnly = false
@@ -25,6 +27,10 @@ func main() {
 	runner.Neo4jUsername = "neo4j"
 	runner.Neo4jPassword = "password"
 	runner.PassBack = true
+	//runner.AccessPathDepth = 2
+	//runner.ContextSensitive = true
+	//runner.UseRTA = true
+	//runner.ImplicitFlow = true
 	err := runner.Run()
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/cokeBeer/goot/pkg/example/dataflow/verybusyexpression"
+)
+
+const src = `package main
+
+func Hello(a int, b int, c bool) int {
+	x := 0
+	if c {
+		x = a - b
+	} else {
+		x = (a - b) * 2
+	}
+	return x
+}`
+
+func main() {
+	runner := verybusyexpression.NewRunner(src, "Hello")
+	runner.Run()
+}
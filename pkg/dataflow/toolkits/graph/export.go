@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/util"
+	"github.com/cokeBeer/goot/pkg/dataflow/util/entry"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Exporter exports a UnitGraph to Graphviz DOT or JSON
+// if Universe is set, nodes are annotated with SCC membership and final facts
+type Exporter struct {
+	Graph    *UnitGraph
+	Universe []*entry.Entry
+}
+
+// ExportedGraph represents a UnitGraph in JSON
+type ExportedGraph struct {
+	Func   string
+	Blocks []*ExportedBlock
+	Nodes  []*ExportedNode
+	Edges  []*ExportedEdge
+}
+
+// ExportedBlock represents a basic block, an empty block has no node in UnitGraph
+type ExportedBlock struct {
+	Index   int
+	Comment string
+	Empty   bool
+	Nodes   []int
+	Succs   []int
+}
+
+// ExportedNode represents an instruction in UnitGraph
+type ExportedNode struct {
+	Index               int
+	Block               int
+	Instruction         string
+	Position            string
+	IsHead              bool
+	IsTail              bool
+	InUniverse          bool
+	IsStronglyConnected bool
+	Facts               map[string]string
+}
+
+// ExportedEdge represents an edge in UnitGraph
+type ExportedEdge struct {
+	From int
+	To   int
+}
+
+// NewExporter returns an Exporter, universe can be nil
+func NewExporter(g *UnitGraph, universe []*entry.Entry) *Exporter {
+	exporter := new(Exporter)
+	exporter.Graph = g
+	exporter.Universe = universe
+	return exporter
+}
+
+// Export builds an ExportedGraph
+func (e *Exporter) Export() *ExportedGraph {
+	g := e.Graph
+	exported := &ExportedGraph{Func: g.Func.String(),
+		Blocks: make([]*ExportedBlock, 0),
+		Nodes:  make([]*ExportedNode, 0),
+		Edges:  make([]*ExportedEdge, 0)}
+
+	entries := make(map[ssa.Instruction]*entry.Entry)
+	for _, v := range e.Universe {
+		entries[v.Data] = v
+	}
+	heads := make(map[ssa.Instruction]bool)
+	for _, inst := range g.Heads {
+		heads[inst] = true
+	}
+	tails := make(map[ssa.Instruction]bool)
+	for _, inst := range g.Tails {
+		tails[inst] = true
+	}
+
+	index := make(map[ssa.Instruction]int)
+	for i, inst := range g.UnitChain {
+		index[inst] = i
+		node := &ExportedNode{Index: i, Block: inst.Block().Index, Instruction: InstructionString(inst),
+			IsHead: heads[inst], IsTail: tails[inst]}
+		if pos := inst.Pos(); pos.IsValid() && g.Func.Prog != nil {
+			node.Position = g.Func.Prog.Fset.Position(pos).String()
+		}
+		if v, ok := entries[inst]; ok {
+			node.InUniverse = true
+			node.IsStronglyConnected = v.IsRealStronglyConnected
+			node.Facts = util.FormatFlow(v.OutFlow)
+		}
+		exported.Nodes = append(exported.Nodes, node)
+	}
+	for _, b := range g.Func.Blocks {
+		block := &ExportedBlock{Index: b.Index, Comment: b.Comment, Empty: len(b.Instrs) == 0,
+			Nodes: make([]int, 0), Succs: make([]int, 0)}
+		for _, inst := range b.Instrs {
+			block.Nodes = append(block.Nodes, index[inst])
+		}
+		for _, succ := range b.Succs {
+			block.Succs = append(block.Succs, succ.Index)
+		}
+		exported.Blocks = append(exported.Blocks, block)
+	}
+	for _, inst := range g.UnitChain {
+		for _, succ := range g.GetSuccs(inst) {
+			exported.Edges = append(exported.Edges, &ExportedEdge{From: index[inst], To: index[succ]})
+		}
+	}
+	return exported
+}
+
+// WriteJSON writes the UnitGraph to w in json format
+func (e *Exporter) WriteJSON(w io.Writer) error {
+	res, err := json.MarshalIndent(e.Export(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(res)
+	return err
+}
+
+// WriteDot writes the UnitGraph to w in Graphviz DOT format
+// instructions are clustered by basic blocks, heads are green, tails are red and nodes in SCC are filled
+func (e *Exporter) WriteDot(w io.Writer) error {
+	exported := e.Export()
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(exported.Func))
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	for _, block := range exported.Blocks {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n", block.Index)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", quote(fmt.Sprintf("%d: %s", block.Index, block.Comment)))
+		if block.Empty {
+			fmt.Fprintf(&b, "\t\tb%d [label=\"(empty)\", shape=plaintext];\n", block.Index)
+		}
+		for _, i := range block.Nodes {
+			node := exported.Nodes[i]
+			attrs := []string{"label=" + nodeLabel(node)}
+			if node.IsHead {
+				attrs = append(attrs, "color=green", "penwidth=2")
+			} else if node.IsTail {
+				attrs = append(attrs, "color=red", "penwidth=2")
+			}
+			if node.IsStronglyConnected {
+				attrs = append(attrs, "style=filled", "fillcolor=lightyellow")
+			}
+			fmt.Fprintf(&b, "\t\tn%d [%s];\n", node.Index, strings.Join(attrs, ", "))
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, edge := range exported.Edges {
+		fmt.Fprintf(&b, "\tn%d -> n%d;\n", edge.From, edge.To)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Persist writes the UnitGraph to dir as <function>.dot and <function>.json
+func (e *Exporter) Persist(dir string) error {
+	name := filepath.Join(dir, util.FileName(e.Graph.Func.String()))
+	f, err := os.OpenFile(name+".dot", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := e.WriteDot(f); err != nil {
+		return err
+	}
+	j, err := os.OpenFile(name+".json", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer j.Close()
+	return e.WriteJSON(j)
+}
+
+// nodeLabel returns a left-justified label of a node with its facts
+func nodeLabel(node *ExportedNode) string {
+	label := escape(node.Instruction) + "\\l"
+	keys := make([]string, 0)
+	for k := range node.Facts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		label += escape(k+"="+node.Facts[k]) + "\\l"
+	}
+	return "\"" + label + "\""
+}
+
+// quote quotes a string as a DOT ID
+func quote(s string) string {
+	return "\"" + escape(s) + "\""
+}
+
+// escape escapes quotes and backslashes in a DOT string
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
+
+// InstructionString formats an instruction like ssa.Function.WriteTo
+func InstructionString(inst ssa.Instruction) string {
+	if v, ok := inst.(ssa.Value); ok {
+		return v.Name() + " = " + v.String()
+	}
+	return inst.String()
+}
@@ -19,6 +19,7 @@ import (
 type Solver struct {
 	Analysis scalar.FlowAnalysis
 	Debug    bool
+	Trace    *Trace
 }
 
 // Solve constructs a Solver and call Solver.DoAnalysis
@@ -44,7 +45,12 @@ func (s *Solver) DoAnalysis() int {
 			return numComputations
 		}
 		s.meetFlows(e)
-		hasChanged := s.flowThrougth(e)
+		var hasChanged bool
+		if s.Trace != nil {
+			hasChanged = s.trace(e)
+		} else {
+			hasChanged = s.flowThrougth(e)
+		}
 		if hasChanged {
 			for _, o := range e.Out {
 				q.Add(o)
@@ -56,6 +62,9 @@ func (s *Solver) DoAnalysis() int {
 				log.Println("has computed", a.GetGraph().Func.String(), "more than max computations, skip")
 				color.Unset()
 			}
+			if s.Trace != nil {
+				s.Trace.Truncated = true
+			}
 			a.End(universe)
 			return numComputations
 		}
@@ -0,0 +1,207 @@
+package solver
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/util"
+	"github.com/cokeBeer/goot/pkg/dataflow/util/entry"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Trace records every step a Solver takes on a flow graph
+type Trace struct {
+	Func      string
+	SSA       string
+	Units     []*TraceUnit
+	Steps     []*TraceStep
+	Truncated bool
+	unitIndex map[ssa.Instruction]int
+}
+
+// TraceUnit represents an instruction in a traced function
+type TraceUnit struct {
+	Index       int
+	Block       int
+	Instruction string
+	Position    string
+}
+
+// TraceStep represents a step of a Solver, the flows are formatted into strings
+type TraceStep struct {
+	Index   int
+	Unit    int
+	InFlow  map[string]string
+	OutFlow map[string]string
+	Changed bool
+}
+
+// NewTrace returns a Trace for a UnitGraph
+func NewTrace(g *graph.UnitGraph) *Trace {
+	trace := new(Trace)
+	trace.Func = g.Func.String()
+	trace.Units = make([]*TraceUnit, 0)
+	trace.Steps = make([]*TraceStep, 0)
+	trace.unitIndex = make(map[ssa.Instruction]int)
+	var buf bytes.Buffer
+	g.Func.WriteTo(&buf)
+	trace.SSA = buf.String()
+	for _, b := range g.Func.Blocks {
+		for _, inst := range b.Instrs {
+			unit := &TraceUnit{Index: len(trace.Units), Block: b.Index, Instruction: graph.InstructionString(inst)}
+			if pos := inst.Pos(); pos.IsValid() && g.Func.Prog != nil {
+				unit.Position = g.Func.Prog.Fset.Position(pos).String()
+			}
+			trace.unitIndex[inst] = unit.Index
+			trace.Units = append(trace.Units, unit)
+		}
+	}
+	return trace
+}
+
+// record appends a step to the Trace
+func (t *Trace) record(e *entry.Entry, in map[string]string, before map[string]string, after map[string]string) {
+	index, ok := t.unitIndex[e.Data]
+	if !ok {
+		index = -1
+	}
+	step := &TraceStep{Index: len(t.Steps), Unit: index, InFlow: in, OutFlow: after, Changed: !reflect.DeepEqual(before, after)}
+	t.Steps = append(t.Steps, step)
+}
+
+// trace runs flowThrougth on an entry and records it as a step
+func (s *Solver) trace(e *entry.Entry) bool {
+	in := util.FormatFlow(e.InFlow)
+	before := util.FormatFlow(e.OutFlow)
+	hasChanged := s.flowThrougth(e)
+	s.Trace.record(e, in, before, util.FormatFlow(e.OutFlow))
+	return hasChanged
+}
+
+// WriteJSON writes the Trace to w in json format
+func (t *Trace) WriteJSON(w io.Writer) error {
+	res, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(res)
+	return err
+}
+
+// WriteHTML writes the Trace to w as an interactive html page
+func (t *Trace) WriteHTML(w io.Writer) error {
+	res, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return traceTemplate.Execute(w, map[string]any{"Func": t.Func, "Data": string(res)})
+}
+
+// Persist writes the Trace to dir as <function>.trace.json and <function>.trace.html
+func (t *Trace) Persist(dir string) error {
+	name := filepath.Join(dir, util.FileName(t.Func))
+	f, err := os.OpenFile(name+".trace.json", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := t.WriteJSON(f); err != nil {
+		return err
+	}
+	h, err := os.OpenFile(name+".trace.html", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+	return t.WriteHTML(h)
+}
+
+var traceTemplate = template.Must(template.New("trace").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Func}}</title>
+<style>
+body { font-family: monospace; margin: 0; display: flex; height: 100vh; }
+#ssa, #steps, #facts { overflow: auto; padding: 8px; border-right: 1px solid #ccc; }
+#ssa { flex: 3; } #steps { flex: 2; } #facts { flex: 4; border-right: none; }
+.block { color: #888; margin-top: 8px; }
+.unit, .step { cursor: pointer; white-space: pre; padding: 1px 4px; }
+.unit:hover, .step:hover { background: #eef; }
+.selected { background: #ffd; }
+.changed { color: #b00; }
+.filtered { display: none; }
+table { border-collapse: collapse; } td, th { border: 1px solid #ddd; padding: 2px 6px; text-align: left; }
+</style>
+</head>
+<body>
+<div id="ssa"><b>{{.Func}}</b><div id="units"></div></div>
+<div id="steps"><b>steps</b> <a href="#" id="all">(show all)</a><div id="list"></div></div>
+<div id="facts"><b>facts</b><div id="detail">click a step or an instruction, use arrow keys to move between steps</div></div>
+<script>
+var trace = JSON.parse({{.Data}});
+var current = -1, filter = -1;
+function el(tag, cls, text) { var e = document.createElement(tag); if (cls) e.className = cls; if (text !== undefined) e.textContent = text; return e; }
+var units = document.getElementById("units"), list = document.getElementById("list"), detail = document.getElementById("detail");
+var block = -1;
+trace.Units.forEach(function (u) {
+	if (u.Block !== block) { block = u.Block; units.appendChild(el("div", "block", block + ":")); }
+	var d = el("div", "unit", "  " + u.Instruction);
+	d.id = "u" + u.Index; d.title = u.Position;
+	d.onclick = function () { filter = u.Index; render(); };
+	units.appendChild(d);
+});
+trace.Steps.forEach(function (s) {
+	var u = trace.Units[s.Unit];
+	var d = el("div", "step" + (s.Changed ? " changed" : ""), s.Index + ": " + (u ? u.Instruction : "?"));
+	d.id = "s" + s.Index;
+	d.onclick = function () { current = s.Index; render(); };
+	list.appendChild(d);
+});
+if (trace.Truncated) list.appendChild(el("div", "changed", "more than max computations, truncated"));
+document.getElementById("all").onclick = function (e) { e.preventDefault(); filter = -1; render(); };
+document.onkeydown = function (e) {
+	if (e.key === "ArrowDown" || e.key === "ArrowUp") {
+		e.preventDefault();
+		var d = e.key === "ArrowDown" ? 1 : -1;
+		for (var i = current + d; i >= 0 && i < trace.Steps.length; i += d) {
+			if (filter < 0 || trace.Steps[i].Unit === filter) { current = i; break; }
+		}
+		render();
+	}
+};
+function render() {
+	document.querySelectorAll(".selected").forEach(function (e) { e.classList.remove("selected"); });
+	trace.Steps.forEach(function (s) { document.getElementById("s" + s.Index).classList.toggle("filtered", filter >= 0 && s.Unit !== filter); });
+	if (filter >= 0) document.getElementById("u" + filter).classList.add("selected");
+	if (current < 0) return;
+	var s = trace.Steps[current];
+	var sd = document.getElementById("s" + current); sd.classList.add("selected"); sd.scrollIntoView({block: "nearest"});
+	var u = trace.Units[s.Unit];
+	if (u) { var ud = document.getElementById("u" + u.Index); ud.classList.add("selected"); ud.scrollIntoView({block: "nearest"}); }
+	detail.innerHTML = "";
+	detail.appendChild(el("div", "", "step " + s.Index + (s.Changed ? " (changed)" : " (unchanged)")));
+	if (u) { detail.appendChild(el("div", "", u.Instruction)); detail.appendChild(el("div", "", u.Position)); }
+	var keys = Object.keys(Object.assign({}, s.InFlow, s.OutFlow)).sort();
+	var table = el("table"), head = el("tr");
+	["key", "in", "out"].forEach(function (h) { head.appendChild(el("th", "", h)); });
+	table.appendChild(head);
+	keys.forEach(function (k) {
+		var tr = el("tr", s.InFlow[k] !== s.OutFlow[k] ? "changed" : "");
+		tr.appendChild(el("td", "", k));
+		tr.appendChild(el("td", "", k in s.InFlow ? s.InFlow[k] : ""));
+		tr.appendChild(el("td", "", k in s.OutFlow ? s.OutFlow[k] : ""));
+		table.appendChild(tr);
+	});
+	detail.appendChild(table);
+}
+</script>
+</body>
+</html>
+`))
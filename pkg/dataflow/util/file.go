@@ -0,0 +1,10 @@
+package util
+
+import "regexp"
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FileName replaces characters unsafe in a file name, e.g. a function's name
+func FileName(name string) string {
+	return unsafeChars.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,15 @@
+package util
+
+import "fmt"
+
+// FormatFlow formats keys and values of a flow into strings
+func FormatFlow(flow *map[any]any) map[string]string {
+	formatted := make(map[string]string)
+	if flow == nil {
+		return formatted
+	}
+	for k, v := range *flow {
+		formatted[fmt.Sprint(k)] = fmt.Sprint(v)
+	}
+	return formatted
+}
@@ -0,0 +1,132 @@
+package availableexpression
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/scalar"
+	"github.com/cokeBeer/goot/pkg/dataflow/util/entry"
+	"github.com/cokeBeer/goot/pkg/example/dataflow/expression"
+	"github.com/dnote/color"
+	"golang.org/x/tools/go/ssa"
+)
+
+// AvailableExpressionAnalysis represents an available expressions analysis
+// it is a forward must analysis, an expression is available at a point if it is computed on every path to the point
+// and none of its operands is redefined after that
+type AvailableExpressionAnalysis struct {
+	scalar.BaseFlowAnalysis
+	expressions  map[string]*expression.Expression
+	Redundancies []*Redundancy
+	GraphDstDir  string
+}
+
+// Redundancy represents a computation of an expression which is already available
+type Redundancy struct {
+	Instruction string
+	Position    string
+	Expression  string
+}
+
+// New creates an AvailableExpressionAnalysis
+func New(g *graph.UnitGraph) *AvailableExpressionAnalysis {
+	availableExpressionAnalysis := new(AvailableExpressionAnalysis)
+	availableExpressionAnalysis.BaseFlowAnalysis = *scalar.NewBase(g)
+	availableExpressionAnalysis.expressions = expression.Collect(g.Func)
+	availableExpressionAnalysis.Redundancies = make([]*Redundancy, 0)
+	return availableExpressionAnalysis
+}
+
+// NewInitalFlow returns a new flow
+// the top of a must analysis is the set of all expressions
+func (a *AvailableExpressionAnalysis) NewInitalFlow() *map[any]any {
+	m := make(map[any]any)
+	for k := range a.expressions {
+		m[k] = true
+	}
+	return &m
+}
+
+// EntryInitalFlow returns a new flow for entry
+// no expression is available at entry
+func (a *AvailableExpressionAnalysis) EntryInitalFlow() *map[any]any {
+	m := make(map[any]any)
+	return &m
+}
+
+// Copy copy from srcMap to dstMap
+// dstMap is cleared first because a must analysis removes facts
+func (a *AvailableExpressionAnalysis) Copy(srcMap *map[any]any, dstMap *map[any]any) {
+	if srcMap == dstMap {
+		return
+	}
+	for k := range *dstMap {
+		delete(*dstMap, k)
+	}
+	for k, v := range *srcMap {
+		(*dstMap)[k] = v
+	}
+}
+
+// MergeInto merge from in to inout based on unit
+// an expression is available only if it is available on all predecessors
+func (a *AvailableExpressionAnalysis) MergeInto(unit ssa.Instruction, inout *map[any]any, in *map[any]any) {
+	for k := range *inout {
+		if _, ok := (*in)[k]; !ok {
+			delete(*inout, k)
+		}
+	}
+}
+
+// FlowThrougth calculate outMap based on inMap and unit
+func (a *AvailableExpressionAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.Instruction, outMap *map[any]any) {
+	a.Copy(inMap, outMap)
+	for k, e := range a.expressions {
+		if expression.Kills(unit, e) {
+			delete(*outMap, k)
+		}
+	}
+	if e := expression.Of(unit); e != nil && !expression.Kills(unit, e) {
+		(*outMap)[e.Key] = true
+	}
+}
+
+// End handle result of analysis
+// a computation of an expression available before it is redundant
+func (a *AvailableExpressionAnalysis) End(universe []*entry.Entry) {
+	if a.GraphDstDir != "" {
+		err := graph.NewExporter(a.Graph, universe).Persist(a.GraphDstDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	for _, v := range universe {
+		color.Set(color.FgGreen)
+		fmt.Println("available expressions after instruction: " + v.Data.String())
+		color.Unset()
+		fmt.Println("{" + strings.Join(expression.Keys(v.OutFlow), ", ") + "}")
+		fmt.Println()
+		if e := expression.Of(v.Data); e != nil {
+			if _, ok := (*v.InFlow)[e.Key]; ok {
+				a.Redundancies = append(a.Redundancies, &Redundancy{Instruction: graph.InstructionString(v.Data),
+					Position: a.position(v.Data), Expression: e.Key})
+			}
+		}
+	}
+	for _, r := range a.Redundancies {
+		color.Set(color.FgYellow)
+		fmt.Printf("redundant computation: %s at %s, %s is already available\n", r.Instruction, r.Position, r.Expression)
+		color.Unset()
+	}
+}
+
+// position returns the position of an instruction in string
+func (a *AvailableExpressionAnalysis) position(inst ssa.Instruction) string {
+	f := a.Graph.Func
+	if pos := inst.Pos(); pos.IsValid() && f.Prog != nil {
+		return f.Prog.Fset.Position(pos).String()
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package availableexpression
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/solver"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Runner represents an available expressions runner
+type Runner struct {
+	Src         string
+	Function    string
+	TraceDstDir string
+	GraphDstDir string
+}
+
+// NewRunner returns a Runner
+func NewRunner(src string, function string) *Runner {
+	runner := new(Runner)
+	runner.Src = src
+	runner.Function = function
+	return runner
+}
+
+// Run kick off the analysis
+func (r *Runner) Run() {
+	// Generate ast
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", r.Src, parser.Mode(0))
+	if err != nil {
+		log.Println(err)
+	}
+	files := []*ast.File{f}
+
+	// Build package
+	pkg := types.NewPackage("availableexpressionanalysis", "")
+	hello, _, err := ssautil.BuildPackage(
+		&types.Config{Importer: importer.Default()}, fset, pkg, files, ssa.SanityCheckFunctions)
+	if err != nil {
+		log.Println(err)
+	}
+
+	// Build graph
+	fn := hello.Func(r.Function)
+	fn.WriteTo(os.Stdout)
+	graph := graph.New(fn)
+
+	// Build analysis
+	analysis := New(graph)
+	analysis.GraphDstDir = r.GraphDstDir
+
+	// Solve analysis, trace every step if needed
+	s := &solver.Solver{Analysis: analysis, Debug: true}
+	if r.TraceDstDir != "" {
+		s.Trace = solver.NewTrace(graph)
+	}
+	s.DoAnalysis()
+
+	if s.Trace != nil {
+		err = s.Trace.Persist(r.TraceDstDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
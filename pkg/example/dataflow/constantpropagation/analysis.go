@@ -2,8 +2,11 @@ package constantpropagation
 
 import (
 	"fmt"
-	"os"
+	"go/constant"
+	"go/token"
+	"log"
 	"sort"
+	"strings"
 
 	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
 	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
@@ -17,6 +20,28 @@ import (
 type ConstantPropagationAnalysis struct {
 	scalar.BaseFlowAnalysis
 	constantPropagationSwitcher *ConstantPropagationSwitcher
+	GraphDstDir                 string
+	Sparse                      bool
+	Conditions                  []*Condition
+	DeadBlocks                  []*DeadBlock
+	Universe                    []*entry.Entry
+	interprocedural             *Interprocedural
+}
+
+// Condition represents an If whose condition is always true or always false
+type Condition struct {
+	Instruction string
+	Position    string
+	Value       bool
+	pos         token.Pos
+}
+
+// DeadBlock represents a basic block which is never executed
+type DeadBlock struct {
+	Index    int
+	Comment  string
+	Position string
+	pos      token.Pos
 }
 
 // New creates a ConstantPropagationAnalysis
@@ -27,19 +52,41 @@ func New(g *graph.UnitGraph) *ConstantPropagationAnalysis {
 	constantPropagationSwitcher.BaseSwitcher = *new(switcher.BaseSwitcher)
 	constanctPropagationAnalysis.constantPropagationSwitcher = constantPropagationSwitcher
 	constantPropagationSwitcher.constanctPropagationAnalysis = constanctPropagationAnalysis
-	constanctPropagationAnalysis.Graph.Func.WriteTo(os.Stdout)
+	constanctPropagationAnalysis.Conditions = make([]*Condition, 0)
+	constanctPropagationAnalysis.DeadBlocks = make([]*DeadBlock, 0)
 	return constanctPropagationAnalysis
 }
 
-// NewInitalFlow returns a new flow
-func (a *ConstantPropagationAnalysis) NewInitalFlow() *map[any]any {
+// EntryInitalFlow returns a new flow for entry
+// parameters are unknown, so they are NAC unless their summaries are known interprocedurally,
+// and the entry is reachable in sparse mode
+func (a *ConstantPropagationAnalysis) EntryInitalFlow() *map[any]any {
 	m := make(map[any]any)
 	for _, v := range a.Graph.Func.Params {
-		m[v.Name()] = "UNDEF"
+		m[v.Name()] = a.constantPropagationSwitcher.lookup(v)
+	}
+	if a.Sparse {
+		m[reachableKey] = true
 	}
 	return &m
 }
 
+// MergeInto merge from in to inout based on unit
+// facts are merged by meet, a value missing in a flow is UNDEF,
+// reachability and executable edges are merged by or
+func (a *ConstantPropagationAnalysis) MergeInto(unit ssa.Instruction, inout *map[any]any, in *map[any]any) {
+	for k, v := range *in {
+		u, ok := (*inout)[k]
+		if !ok {
+			(*inout)[k] = v
+		} else if strings.HasPrefix(k.(string), "@") {
+			(*inout)[k] = u == true || v == true
+		} else {
+			(*inout)[k] = meet(u, v)
+		}
+	}
+}
+
 // FlowThrougth calculate outMap based on inMap and unit
 func (a *ConstantPropagationAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.Instruction, outMap *map[any]any) {
 	a.Copy(inMap, outMap)
@@ -47,16 +94,31 @@ func (a *ConstantPropagationAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.
 }
 
 // End handle result of analysis
+// the universe is kept for Print and Facts
 func (a *ConstantPropagationAnalysis) End(universe []*entry.Entry) {
-	for _, v := range universe {
+	a.Universe = universe
+	if a.GraphDstDir != "" {
+		err := graph.NewExporter(a.Graph, universe).Persist(a.GraphDstDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	if a.Sparse {
+		a.report(universe)
+	}
+}
+
+// Print prints facts of every instruction, and constant conditions and dead blocks in sparse mode
+func (a *ConstantPropagationAnalysis) Print() {
+	for _, v := range a.Universe {
 		color.Set(color.FgGreen)
 		fmt.Println("constant fact for instruction: " + (*v).Data.String())
 		color.Unset()
-		keys := make([]string, len(*v.OutFlow))
-		i := 0
+		keys := make([]string, 0, len(*v.OutFlow))
 		for k := range *v.OutFlow {
-			keys[i] = k.(string)
-			i++
+			if !strings.HasPrefix(k.(string), "@") {
+				keys = append(keys, k.(string))
+			}
 		}
 		sort.Strings(keys)
 		for _, k := range keys {
@@ -65,9 +127,125 @@ func (a *ConstantPropagationAnalysis) End(universe []*entry.Entry) {
 		fmt.Println()
 		fmt.Println()
 	}
+	color.Set(color.FgYellow)
+	for _, c := range a.Conditions {
+		fmt.Printf("condition is always %v: %s at %s\n", c.Value, c.Instruction, c.Position)
+	}
+	for _, b := range a.DeadBlocks {
+		fmt.Printf("block is never executed: %d %s at %s\n", b.Index, b.Comment, b.Position)
+	}
+	color.Unset()
+}
+
+// Fact returns the fact of a value in a flow
+func (a *ConstantPropagationAnalysis) Fact(flow *map[any]any, v ssa.Value) any {
+	a.constantPropagationSwitcher.outMap = flow
+	return a.constantPropagationSwitcher.lookup(v)
+}
+
+// isReachable returns whether an entry is reachable, every entry is reachable if not in sparse mode
+func (a *ConstantPropagationAnalysis) isReachable(e *entry.Entry) bool {
+	return !a.Sparse || (*e.OutFlow)[reachableKey] == true
+}
+
+// report records constant conditions and dead blocks found in sparse mode
+func (a *ConstantPropagationAnalysis) report(universe []*entry.Entry) {
+	reachable := make(map[*ssa.BasicBlock]bool)
+	for _, v := range universe {
+		if !a.isReachable(v) {
+			continue
+		}
+		reachable[v.Data.Block()] = true
+		if inst, ok := v.Data.(*ssa.If); ok {
+			if c, ok := a.Fact(v.OutFlow, inst.Cond).(constant.Value); ok {
+				pos := ifPosition(inst)
+				a.Conditions = append(a.Conditions, &Condition{Instruction: inst.String(),
+					Position: a.position(pos), Value: constant.BoolVal(c), pos: pos})
+			}
+		}
+	}
+	for _, b := range a.Graph.Func.Blocks {
+		if reachable[b] || len(b.Instrs) == 0 {
+			continue
+		}
+		pos := blockPosition(b)
+		for _, p := range b.Preds {
+			if inst, ok := p.Instrs[len(p.Instrs)-1].(*ssa.If); ok && !pos.IsValid() {
+				pos = ifPosition(inst)
+			}
+		}
+		a.DeadBlocks = append(a.DeadBlocks, &DeadBlock{Index: b.Index, Comment: b.Comment, Position: a.position(pos), pos: pos})
+	}
+}
+
+// position returns a position in string, an invalid position falls back to the function
+func (a *ConstantPropagationAnalysis) position(pos token.Pos) string {
+	f := a.Graph.Func
+	if !pos.IsValid() {
+		pos = f.Pos()
+	}
+	if pos.IsValid() && f.Prog != nil {
+		return f.Prog.Fset.Position(pos).String()
+	}
+	return ""
+}
+
+// ifPosition returns the position of the condition of an If
+// a constant condition has no position, so the nearest instruction before it is used
+func ifPosition(inst *ssa.If) token.Pos {
+	if pos := inst.Cond.Pos(); pos.IsValid() {
+		return pos
+	}
+	instrs := inst.Block().Instrs
+	for i := len(instrs) - 1; i >= 0; i-- {
+		if pos := instrs[i].Pos(); pos.IsValid() {
+			return pos
+		}
+	}
+	return blockPosition(inst.Block().Succs[0])
+}
+
+// blockPosition returns the position of the first instruction with a position in a block
+func blockPosition(b *ssa.BasicBlock) token.Pos {
+	for _, inst := range b.Instrs {
+		if pos := inst.Pos(); pos.IsValid() {
+			return pos
+		}
+	}
+	return token.NoPos
+}
+
+// reach updates reachability at the first instruction of a block and returns whether inst is reachable
+// a block is reachable if it is the entry or one of the edges to it is executable
+func (a *ConstantPropagationAnalysis) reach(inMap *map[any]any, inst ssa.Instruction, outMap *map[any]any) bool {
+	b := inst.Block()
+	if inst == b.Instrs[0] {
+		reachable := b.Index == 0 && (*inMap)[reachableKey] == true
+		for _, p := range b.Preds {
+			if (*inMap)[edgeKey(p, b)] == true {
+				reachable = true
+			}
+		}
+		(*outMap)[reachableKey] = reachable
+	}
+	return (*outMap)[reachableKey] == true
 }
 
 func (a *ConstantPropagationAnalysis) apply(inMap *map[any]any, inst ssa.Instruction, outMap *map[any]any) {
+	if a.Sparse && !a.reach(inMap, inst, outMap) {
+		// an unreachable instruction passes its flow through
+		return
+	}
+	switch inst.(type) {
+	case *ssa.BinOp, *ssa.UnOp, *ssa.Convert, *ssa.ChangeType, *ssa.Phi, *ssa.Call, *ssa.Extract:
+	case *ssa.Range:
+		// an iterator of a range is never a constant
+	default:
+		// a basic value is NAC unless the switcher knows how to evaluate it
+		if v, ok := inst.(ssa.Value); ok && isBasic(v.Type()) {
+			(*outMap)[v.Name()] = NAC
+		}
+	}
 	a.constantPropagationSwitcher.inMap = inMap
 	a.constantPropagationSwitcher.outMap = outMap
 	switcher.Apply(a.constantPropagationSwitcher, inst)
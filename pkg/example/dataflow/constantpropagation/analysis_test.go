@@ -0,0 +1,183 @@
+package constantpropagation
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/solver"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// build builds a package from a source string
+func build(t *testing.T, src string) *ssa.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "test.go", src, parser.Mode(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{Importer: importer.Default()}, fset,
+		types.NewPackage("example.com/test", ""), []*ast.File{f}, ssa.SanityCheckFunctions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+// solve solves constant propagation on a function of a package
+func solve(t *testing.T, pkg *ssa.Package, name string, sparse bool) *ConstantPropagationAnalysis {
+	t.Helper()
+	f := pkg.Func(name)
+	if f == nil {
+		t.Fatalf("no function %s", name)
+	}
+	a := New(graph.New(f))
+	a.Sparse = sparse
+	solver.Solve(a, false)
+	return a
+}
+
+// returned returns the fact of the first result at the only return of an analyzed function
+func returned(t *testing.T, a *ConstantPropagationAnalysis) any {
+	t.Helper()
+	for _, v := range a.Universe {
+		if inst, ok := v.Data.(*ssa.Return); ok {
+			return a.Fact(v.InFlow, inst.Results[0])
+		}
+	}
+	t.Fatalf("no return in %s", a.Graph.Func)
+	return nil
+}
+
+// assertFact checks a fact is NAC, UNDEF or a constant with the exact string want
+func assertFact(t *testing.T, fact any, want string) {
+	t.Helper()
+	got := factString(fact)
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConstantPropagation(t *testing.T) {
+	pkg := build(t, `package test
+
+func Arith() int {
+	x := 1
+	y := x + 2
+	return y * 3
+}
+
+func Wrap() int8 {
+	var x int8 = 127
+	return x + 1
+}
+
+func DivZero() int {
+	x := 0
+	return 1 / x
+}
+
+func Param(p int) int {
+	return p + 1
+}
+
+func Strings() string {
+	s := "a"
+	return s + "b"
+}
+
+func SamePhi(b bool) int {
+	x := 1
+	if b {
+		x = 1
+	}
+	return x
+}
+
+func DiffPhi(b bool) int {
+	x := 1
+	if b {
+		x = 2
+	}
+	return x
+}
+
+func Range(m map[string]int, s string) int {
+	n := 0
+	for _, v := range m {
+		n += v
+	}
+	for range s {
+		n++
+	}
+	return n
+}
+`)
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"Arith", "9"},
+		{"Wrap", "-128"},
+		{"DivZero", NAC},
+		{"Param", NAC},
+		{"Strings", `"ab"`},
+		{"SamePhi", "1"},
+		{"DiffPhi", NAC},
+		{"Range", NAC},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertFact(t, returned(t, solve(t, pkg, c.name, false)), c.want)
+		})
+	}
+}
+
+func TestMeet(t *testing.T) {
+	one := constant.MakeInt64(1)
+	cases := []struct {
+		x, y any
+		want string
+	}{
+		{UNDEF, one, "1"},
+		{one, UNDEF, "1"},
+		{one, constant.MakeInt64(1), "1"},
+		{one, constant.MakeInt64(2), NAC},
+		{one, constant.MakeFloat64(1), NAC},
+		{NAC, UNDEF, NAC},
+		{UNDEF, UNDEF, UNDEF},
+	}
+	for _, c := range cases {
+		assertFact(t, meet(c.x, c.y), c.want)
+	}
+}
+
+func TestSparse(t *testing.T) {
+	pkg := build(t, `package test
+
+func Dead() int {
+	x := 1
+	y := 1
+	if x > 2 {
+		y = 2
+	}
+	return y
+}
+`)
+	assertFact(t, returned(t, solve(t, pkg, "Dead", false)), NAC)
+	a := solve(t, pkg, "Dead", true)
+	assertFact(t, returned(t, a), "1")
+	if len(a.Conditions) != 1 || a.Conditions[0].Value {
+		t.Errorf("got conditions %v, want one always false", a.Conditions)
+	}
+	if len(a.DeadBlocks) != 1 || a.DeadBlocks[0].Comment != "if.then" {
+		t.Errorf("got dead blocks %v, want if.then", a.DeadBlocks)
+	}
+}
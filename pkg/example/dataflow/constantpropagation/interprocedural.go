@@ -0,0 +1,339 @@
+package constantpropagation
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Interprocedural runs constant propagation on all functions of a package
+// constant arguments flow into callees, constant results flow back to callers,
+// and globals initialized once in init are constants
+// a function may be analyzed many times, the trace and graph saved to TraceDstDir and GraphDstDir are of the last time
+type Interprocedural struct {
+	Funcs       []*ssa.Function
+	Sparse      bool
+	TraceDstDir string
+	GraphDstDir string
+	Analyses    map[*ssa.Function]*ConstantPropagationAnalysis
+	params      map[*ssa.Parameter]any
+	results     map[*ssa.Function][]any
+	globals     map[*ssa.Global]any
+	stores      map[*ssa.Global]*ssa.Store
+	callers     map[*ssa.Function][]*ssa.Function
+	readers     map[*ssa.Global][]*ssa.Function
+	worklist    []*ssa.Function
+	queued      map[*ssa.Function]bool
+}
+
+// NewInterprocedural returns an Interprocedural for functions with body in a package
+func NewInterprocedural(pkg *ssa.Package) *Interprocedural {
+	interprocedural := new(Interprocedural)
+	interprocedural.Funcs = packageFuncs(pkg)
+	interprocedural.Analyses = make(map[*ssa.Function]*ConstantPropagationAnalysis)
+	interprocedural.params = make(map[*ssa.Parameter]any)
+	interprocedural.results = make(map[*ssa.Function][]any)
+	interprocedural.globals = make(map[*ssa.Global]any)
+	interprocedural.stores = make(map[*ssa.Global]*ssa.Store)
+	interprocedural.callers = make(map[*ssa.Function][]*ssa.Function)
+	interprocedural.readers = make(map[*ssa.Global][]*ssa.Function)
+	interprocedural.worklist = make([]*ssa.Function, 0)
+	interprocedural.queued = make(map[*ssa.Function]bool)
+	interprocedural.init(pkg)
+	return interprocedural
+}
+
+// init finds callers of functions and readers of globals, and sets up summaries
+// parameters of functions which can be called from unknown places are NAC, others start from UNDEF
+func (ip *Interprocedural) init(pkg *ssa.Package) {
+	addressTaken := make(map[*ssa.Function]bool)
+	escaped := make(map[*ssa.Global]bool)
+	stores := make(map[*ssa.Global][]*ssa.Store)
+	converted := make([]types.Type, 0)
+	for _, f := range ip.Funcs {
+		for _, b := range f.Blocks {
+			for _, inst := range b.Instrs {
+				if m, ok := inst.(*ssa.MakeInterface); ok {
+					converted = append(converted, m.X.Type())
+				}
+				var callee *ssa.Value
+				if c, ok := inst.(ssa.CallInstruction); ok {
+					callee = &c.Common().Value
+				}
+				for _, op := range inst.Operands(nil) {
+					switch v := (*op).(type) {
+					case *ssa.Function:
+						if op == callee {
+							ip.callers[v] = appendFunc(ip.callers[v], f)
+						} else {
+							addressTaken[v] = true
+						}
+					case *ssa.Global:
+						if store, ok := inst.(*ssa.Store); ok && op == &store.Addr && isInit(f) {
+							stores[v] = append(stores[v], store)
+						} else if load, ok := inst.(*ssa.UnOp); ok && op == &load.X && load.Op == token.MUL {
+							ip.readers[v] = appendFunc(ip.readers[v], f)
+						} else {
+							escaped[v] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// a method may be called through an interface
+	methods := make(map[*ssa.Function]bool)
+	for _, t := range converted {
+		for _, ptr := range []types.Type{t, types.NewPointer(t)} {
+			ms := pkg.Prog.MethodSets.MethodSet(ptr)
+			for i := 0; i < ms.Len(); i++ {
+				methods[pkg.Prog.MethodValue(ms.At(i))] = true
+			}
+		}
+	}
+	for _, f := range ip.Funcs {
+		ip.results[f] = make([]any, f.Signature.Results().Len())
+		for i := range ip.results[f] {
+			ip.results[f][i] = UNDEF
+		}
+		isRoot := addressTaken[f] || methods[f] || len(ip.callers[f]) == 0 || isInit(f) ||
+			f.Name() == "main" || (pkg.Pkg.Name() != "main" && f.Object() != nil && f.Object().Exported())
+		for _, p := range f.Params {
+			if isRoot {
+				ip.params[p] = NAC
+			} else {
+				ip.params[p] = UNDEF
+			}
+		}
+	}
+
+	// a global of basic type is constant if it is only stored once in init and only loaded elsewhere
+	// exported globals of a library can be stored by other packages, and a store which may be skipped keeps the zero value
+	for _, m := range pkg.Members {
+		g, ok := m.(*ssa.Global)
+		if !ok {
+			continue
+		}
+		t := g.Type().(*types.Pointer).Elem()
+		switch {
+		case !isBasic(t) || escaped[g] || len(stores[g]) > 1 ||
+			(pkg.Pkg.Name() != "main" && g.Object() != nil && g.Object().Exported()):
+			ip.globals[g] = NAC
+		case len(stores[g]) == 1:
+			ip.globals[g] = UNDEF
+			ip.stores[g] = stores[g][0]
+			if !dominatesExits(stores[g][0]) {
+				ip.globals[g] = zero(t)
+			}
+		default:
+			ip.globals[g] = zero(t)
+		}
+	}
+}
+
+// Run analyzes functions until all summaries are stable
+func (ip *Interprocedural) Run() {
+	for _, f := range ip.Funcs {
+		if isInit(f) {
+			ip.enqueue(f)
+		}
+	}
+	for _, f := range ip.Funcs {
+		ip.enqueue(f)
+	}
+	for len(ip.worklist) != 0 {
+		f := ip.worklist[0]
+		ip.worklist = ip.worklist[1:]
+		ip.queued[f] = false
+		ip.analyze(f)
+	}
+}
+
+// analyze runs constant propagation on a function and updates summaries
+func (ip *Interprocedural) analyze(f *ssa.Function) {
+	a := New(graph.New(f))
+	a.Sparse = ip.Sparse
+	a.GraphDstDir = ip.GraphDstDir
+	a.interprocedural = ip
+	doAnalysis(a, false, ip.TraceDstDir)
+	ip.Analyses[f] = a
+
+	results := ip.results[f]
+	before := make([]any, len(results))
+	copy(before, results)
+	for _, v := range a.Universe {
+		if !a.isReachable(v) {
+			continue
+		}
+		switch inst := v.Data.(type) {
+		case *ssa.Return:
+			for i, r := range inst.Results {
+				results[i] = meet(results[i], a.Fact(v.InFlow, r))
+			}
+		case ssa.CallInstruction:
+			callee := inst.Common().StaticCallee()
+			if callee == nil || ip.results[callee] == nil {
+				continue
+			}
+			changed := false
+			for i, arg := range inst.Common().Args {
+				p := callee.Params[i]
+				fact := meet(ip.params[p], a.Fact(v.InFlow, arg))
+				if !same(fact, ip.params[p]) {
+					ip.params[p] = fact
+					changed = true
+				}
+			}
+			if changed {
+				ip.enqueue(callee)
+			}
+		case *ssa.Store:
+			g, ok := inst.Addr.(*ssa.Global)
+			if !ok || ip.stores[g] != inst {
+				continue
+			}
+			fact := meet(ip.globals[g], a.Fact(v.InFlow, inst.Val))
+			if !same(fact, ip.globals[g]) {
+				ip.globals[g] = fact
+				for _, reader := range ip.readers[g] {
+					ip.enqueue(reader)
+				}
+			}
+		}
+	}
+	for i := range results {
+		if !same(results[i], before[i]) {
+			for _, caller := range ip.callers[f] {
+				ip.enqueue(caller)
+			}
+			break
+		}
+	}
+}
+
+// enqueue adds a function to the worklist if it is not queued
+func (ip *Interprocedural) enqueue(f *ssa.Function) {
+	if ip.queued[f] {
+		return
+	}
+	ip.queued[f] = true
+	ip.worklist = append(ip.worklist, f)
+}
+
+// param returns the summary of a parameter
+func (ip *Interprocedural) param(p *ssa.Parameter) any {
+	if fact, ok := ip.params[p]; ok {
+		return fact
+	}
+	return NAC
+}
+
+// result returns the summary of the i-th result of a function, a function without body returns NAC
+func (ip *Interprocedural) result(f *ssa.Function, i int) any {
+	if results, ok := ip.results[f]; ok {
+		return results[i]
+	}
+	return NAC
+}
+
+// global returns the fact of a global
+func (ip *Interprocedural) global(g *ssa.Global) any {
+	if fact, ok := ip.globals[g]; ok {
+		return fact
+	}
+	return NAC
+}
+
+// packageFuncs returns functions with body in a package, including anonymous functions, sorted by name
+func packageFuncs(pkg *ssa.Package) []*ssa.Function {
+	funcs := make([]*ssa.Function, 0)
+	for f := range ssautil.AllFunctions(pkg.Prog) {
+		if f.Pkg == pkg && f.Blocks != nil {
+			funcs = append(funcs, f)
+		}
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].String() < funcs[j].String()
+	})
+	return funcs
+}
+
+// isInit returns whether a function is the package initializer or an init function
+func isInit(f *ssa.Function) bool {
+	return f.Name() == "init" || strings.HasPrefix(f.Name(), "init#")
+}
+
+// dominatesExits returns whether an instruction is executed whenever its function returns
+// init functions are called unconditionally by the package initializer, so such a store in them always happens,
+// and the package initializer returns at once if the package is initialized, so the edge from its guard is not counted
+func dominatesExits(inst ssa.Instruction) bool {
+	b := inst.Block()
+	for _, exit := range inst.Parent().Blocks {
+		if _, ok := exit.Instrs[len(exit.Instrs)-1].(*ssa.Return); !ok || b.Dominates(exit) {
+			continue
+		}
+		for _, pred := range exit.Preds {
+			if !b.Dominates(pred) && !isGuard(pred) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isGuard returns whether a block branches on init$guard of a package initializer
+func isGuard(b *ssa.BasicBlock) bool {
+	branch, ok := b.Instrs[len(b.Instrs)-1].(*ssa.If)
+	if !ok {
+		return false
+	}
+	load, ok := branch.Cond.(*ssa.UnOp)
+	if !ok {
+		return false
+	}
+	g, ok := load.X.(*ssa.Global)
+	return ok && g.Name() == "init$guard"
+}
+
+// zero returns the zero value of a basic type
+func zero(t types.Type) any {
+	info := t.Underlying().(*types.Basic).Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return constant.MakeBool(false)
+	case info&types.IsString != 0:
+		return constant.MakeString("")
+	case info&types.IsInteger != 0:
+		return constant.MakeInt64(0)
+	case info&types.IsFloat != 0:
+		return constant.MakeFloat64(0)
+	case info&types.IsComplex != 0:
+		return constant.MakeImag(constant.MakeInt64(0))
+	}
+	return NAC
+}
+
+// same returns whether two facts are the same
+func same(x any, y any) bool {
+	if x == NAC || x == UNDEF || y == NAC || y == UNDEF {
+		return x == y
+	}
+	return equal(x.(constant.Value), y.(constant.Value))
+}
+
+// appendFunc appends a function to a list if it is not in the list
+func appendFunc(funcs []*ssa.Function, f *ssa.Function) []*ssa.Function {
+	for _, v := range funcs {
+		if v == f {
+			return funcs
+		}
+	}
+	return append(funcs, f)
+}
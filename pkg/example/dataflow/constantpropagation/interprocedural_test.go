@@ -0,0 +1,93 @@
+package constantpropagation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/util"
+)
+
+// summary returns the summary of the first result of a function
+func summary(t *testing.T, ip *Interprocedural, name string) any {
+	t.Helper()
+	f := ip.Funcs[0].Pkg.Func(name)
+	if f == nil {
+		t.Fatalf("no function %s", name)
+	}
+	return ip.result(f, 0)
+}
+
+func TestInterprocedural(t *testing.T) {
+	src := `package %s
+
+import "os"
+
+var Exported = 5
+var once = 5
+var never int
+var cond int
+var always int
+
+func init() {
+	if os.Getenv("A") != "" {
+		cond = 5
+	}
+	always = 3
+}
+
+func exported() int { return Exported }
+func readOnce() int { return once }
+func readNever() int { return never }
+func readCond() int { return cond }
+func readAlways() int { return always }
+
+func inc(a int) int { return a + 1 }
+func call() int { return inc(1) }
+`
+	cases := []struct {
+		name string
+		lib  string
+		main string
+	}{
+		{"exported", NAC, "5"},
+		{"readOnce", "5", "5"},
+		{"readNever", "0", "0"},
+		{"readCond", NAC, NAC},
+		{"readAlways", "3", "3"},
+		{"call", "2", "2"},
+	}
+	for _, name := range []string{"lib", "main"} {
+		ip := NewInterprocedural(build(t, fmt.Sprintf(src, name)))
+		ip.Run()
+		for _, c := range cases {
+			want := c.lib
+			if name == "main" {
+				want = c.main
+			}
+			t.Run(name+"/"+c.name, func(t *testing.T) {
+				assertFact(t, summary(t, ip, c.name), want)
+			})
+		}
+	}
+}
+
+func TestInterproceduralOutput(t *testing.T) {
+	dir := t.TempDir()
+	ip := NewInterprocedural(build(t, `package test
+
+func inc(a int) int { return a + 1 }
+func call() int { return inc(1) }
+`))
+	ip.TraceDstDir = dir
+	ip.GraphDstDir = dir
+	ip.Run()
+	for _, f := range ip.Funcs {
+		for _, ext := range []string{".trace.json", ".trace.html", ".dot", ".json"} {
+			if _, err := os.Stat(filepath.Join(dir, util.FileName(f.String())+ext)); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package constantpropagation
+
+import (
+	"bytes"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// FunctionFacts represents constant facts of a function
+type FunctionFacts struct {
+	Function     string
+	Position     string
+	Instructions []*InstructionFacts
+	Conditions   []*Condition
+	DeadBlocks   []*DeadBlock
+}
+
+// InstructionFacts represents constant facts after an instruction
+// Value is the fact of the value defined by the instruction, empty if it defines no basic value
+type InstructionFacts struct {
+	Instruction string
+	Position    string
+	Reachable   bool
+	Value       string `json:",omitempty"`
+	Facts       map[string]string
+}
+
+// Facts returns facts of a solved analysis in block order
+func (a *ConstantPropagationAnalysis) Facts() *FunctionFacts {
+	f := a.Graph.Func
+	functionFacts := new(FunctionFacts)
+	functionFacts.Function = f.String()
+	functionFacts.Position = a.position(f.Pos())
+	functionFacts.Instructions = make([]*InstructionFacts, 0, len(a.Universe))
+	functionFacts.Conditions = a.Conditions
+	functionFacts.DeadBlocks = a.DeadBlocks
+	for _, v := range a.Universe {
+		instructionFacts := new(InstructionFacts)
+		instructionFacts.Instruction = v.Data.String()
+		if pos := v.Data.Pos(); pos.IsValid() {
+			instructionFacts.Position = f.Prog.Fset.Position(pos).String()
+		}
+		instructionFacts.Reachable = a.isReachable(v)
+		if value, ok := v.Data.(ssa.Value); ok && isBasic(value.Type()) {
+			instructionFacts.Value = factString(a.Fact(v.OutFlow, value))
+		}
+		instructionFacts.Facts = make(map[string]string)
+		for k, fact := range *v.OutFlow {
+			if !strings.HasPrefix(k.(string), "@") {
+				instructionFacts.Facts[k.(string)] = factString(fact)
+			}
+		}
+		functionFacts.Instructions = append(functionFacts.Instructions, instructionFacts)
+	}
+	return functionFacts
+}
+
+// factString returns a fact in string, a constant is written exactly, so a long string is not cut
+func factString(fact any) string {
+	if c, ok := fact.(constant.Value); ok {
+		return c.ExactString()
+	}
+	return fmt.Sprint(fact)
+}
+
+// Annotate returns source of analyzed functions with constant values and dead code as line comments
+// src is used for functions without a file name, which are built from a source string
+func Annotate(analyses []*ConstantPropagationAnalysis, src string) ([]byte, error) {
+	// Collect annotations by file and line, anonymous functions are shown in their enclosing functions
+	notes := make(map[string]map[int][]string)
+	note := func(p token.Position, text string) {
+		file, line := p.Filename, p.Line
+		if notes[file] == nil {
+			notes[file] = make(map[int][]string)
+		}
+		for _, t := range notes[file][line] {
+			if t == text {
+				return
+			}
+		}
+		notes[file][line] = append(notes[file][line], text)
+	}
+	roots := make([]*ssa.Function, 0)
+	for _, a := range analyses {
+		fset := a.Graph.Func.Prog.Fset
+		for _, v := range a.Universe {
+			value, ok := v.Data.(ssa.Value)
+			if !ok || !v.Data.Pos().IsValid() || !a.isReachable(v) {
+				continue
+			}
+			if _, ok := value.(*ssa.Phi); ok {
+				continue
+			}
+			if c, ok := a.Fact(v.OutFlow, value).(constant.Value); ok {
+				note(fset.Position(v.Data.Pos()), fmt.Sprintf("%s = %s", v.Data.String(), c.ExactString()))
+			}
+		}
+		for _, c := range a.Conditions {
+			note(fset.Position(c.pos), fmt.Sprintf("condition is always %v", c.Value))
+		}
+		for _, b := range a.DeadBlocks {
+			note(fset.Position(b.pos), fmt.Sprintf("%s is never executed", b.Comment))
+		}
+		root := a.Graph.Func
+		for root.Parent() != nil {
+			root = root.Parent()
+		}
+		if root.Syntax() != nil && root.Pos().IsValid() {
+			roots = appendFunc(roots, root)
+		}
+	}
+
+	// Write source lines of every enclosing function with annotations
+	var buf bytes.Buffer
+	files := make(map[string][]string)
+	for _, f := range roots {
+		fset := f.Prog.Fset
+		start := fset.Position(f.Syntax().Pos())
+		end := fset.Position(f.Syntax().End())
+		lines, ok := files[start.Filename]
+		if !ok {
+			content := []byte(src)
+			if start.Filename != "" {
+				var err error
+				content, err = os.ReadFile(start.Filename)
+				if err != nil {
+					return nil, err
+				}
+			}
+			lines = strings.Split(string(content), "\n")
+			files[start.Filename] = lines
+		}
+		fmt.Fprintf(&buf, "// %s at %s\n", f.String(), start)
+		for line := start.Line; line <= end.Line && line <= len(lines); line++ {
+			text := strings.TrimRight(lines[line-1], " \t\r")
+			if n := notes[start.Filename][line]; len(n) != 0 {
+				sort.Strings(n)
+				text += " // " + strings.Join(n, "; ")
+			}
+			fmt.Fprintf(&buf, "%4d | %s\n", line, text)
+		}
+		fmt.Fprintln(&buf)
+	}
+	return buf.Bytes(), nil
+}
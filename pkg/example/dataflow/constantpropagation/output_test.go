@@ -0,0 +1,24 @@
+package constantpropagation
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFactsExact(t *testing.T) {
+	query := "SELECT id, name, email, created_at FROM users WHERE deleted_at IS NULL ORDER BY created_at"
+	pkg := build(t, `package test
+
+func Query() string {
+	q := "SELECT id, name, email, created_at FROM users "
+	return q + "WHERE deleted_at IS NULL ORDER BY created_at"
+}
+`)
+	facts := solve(t, pkg, "Query", false).Facts()
+	for _, inst := range facts.Instructions {
+		if inst.Value == strconv.Quote(query) {
+			return
+		}
+	}
+	t.Errorf("no fact of %q in %+v", query, facts.Instructions)
+}
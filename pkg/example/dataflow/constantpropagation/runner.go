@@ -1,56 +1,216 @@
 package constantpropagation
 
 import (
+	"encoding/json"
+	"errors"
 	"go/ast"
 	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"log"
+	"os"
 
 	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
 	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/solver"
+	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 // Runner represents a constant propagation runner
+// it analyzes Src if it is set, otherwise it loads packages in PkgPath
 type Runner struct {
-	Src      string
-	Function string
+	Src             string
+	PkgPath         []string
+	Function        string
+	Functions       []string
+	TraceDstDir     string
+	GraphDstDir     string
+	Sparse          bool
+	Interprocedural bool
+	Format          string
+	DstPath         string
+	DumpSSA         bool
 }
 
+// NewRunner returns a Runner for a source string, which dumps SSA and prints facts of a function in text
 func NewRunner(src string, function string) *Runner {
 	runner := new(Runner)
 	runner.Src = src
 	runner.Function = function
+	runner.Format = "text"
+	runner.DumpSSA = true
+	return runner
+}
+
+// NewPackageRunner returns a Runner for package patterns, which prints facts of all functions in json
+func NewPackageRunner(pkgPath ...string) *Runner {
+	runner := new(Runner)
+	runner.PkgPath = pkgPath
+	runner.Functions = make([]string, 0)
+	runner.Format = "json"
 	return runner
 }
 
 // Run kick off the analysis
-func (r *Runner) Run() {
-	// Generate ast
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, "", r.Src, parser.Mode(0))
+func (r *Runner) Run() error {
+	pkgs, err := r.load()
 	if err != nil {
-		log.Println(err)
+		return err
 	}
-	files := []*ast.File{f}
 
-	// Build package
-	pkg := types.NewPackage("constantpropagtionanalysis", "")
-	hello, _, err := ssautil.BuildPackage(
-		&types.Config{Importer: importer.Default()}, fset, pkg, files, ssa.SanityCheckFunctions)
+	// Solve analyses on target functions, interprocedurally if needed
+	analyses := make([]*ConstantPropagationAnalysis, 0)
+	for _, pkg := range pkgs {
+		if r.Interprocedural {
+			ip := NewInterprocedural(pkg)
+			ip.Sparse = r.Sparse
+			ip.TraceDstDir = r.TraceDstDir
+			ip.GraphDstDir = r.GraphDstDir
+			ip.Run()
+			for _, f := range ip.Funcs {
+				if r.isTarget(f) {
+					analyses = append(analyses, ip.Analyses[f])
+				}
+			}
+			continue
+		}
+		for _, f := range packageFuncs(pkg) {
+			if r.isTarget(f) {
+				analyses = append(analyses, r.solve(f))
+			}
+		}
+	}
+
+	// Write facts
+	if r.Format == "text" {
+		for _, a := range analyses {
+			if r.DumpSSA {
+				a.Graph.Func.WriteTo(os.Stdout)
+			}
+			a.Print()
+		}
+		return nil
+	}
+	if r.DumpSSA {
+		for _, a := range analyses {
+			a.Graph.Func.WriteTo(os.Stdout)
+		}
+	}
+	var res []byte
+	switch r.Format {
+	case "json":
+		facts := make([]*FunctionFacts, 0, len(analyses))
+		for _, a := range analyses {
+			facts = append(facts, a.Facts())
+		}
+		res, err = json.MarshalIndent(facts, "", "    ")
+		res = append(res, '\n')
+	case "annotated":
+		res, err = Annotate(analyses, r.Src)
+	default:
+		err = errors.New("unknown format " + r.Format)
+	}
 	if err != nil {
-		log.Println(err)
+		return err
+	}
+	if r.DstPath == "" {
+		_, err = os.Stdout.Write(res)
+		return err
+	}
+	return os.WriteFile(r.DstPath, res, 0666)
+}
+
+// load builds SSA packages from Src or PkgPath
+func (r *Runner) load() ([]*ssa.Package, error) {
+	if r.Src != "" {
+		// Generate ast
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", r.Src, parser.Mode(0))
+		if err != nil {
+			return nil, err
+		}
+		files := []*ast.File{f}
+
+		// Build package
+		pkg := types.NewPackage("constantpropagtionanalysis", "")
+		hello, _, err := ssautil.BuildPackage(
+			&types.Config{Importer: importer.Default()}, fset, pkg, files, ssa.SanityCheckFunctions)
+		if err != nil {
+			return nil, err
+		}
+		return []*ssa.Package{hello}, nil
 	}
 
-	// Build graph
-	graph := graph.New(hello.Func(r.Function))
+	mode := packages.NeedName |
+		packages.NeedFiles |
+		packages.NeedCompiledGoFiles |
+		packages.NeedSyntax |
+		packages.NeedTypesInfo |
+		packages.NeedImports |
+		packages.NeedTypesSizes |
+		packages.NeedTypes |
+		packages.NeedDeps
+	cfg := &packages.Config{Mode: mode}
+	initial, err := packages.Load(cfg, r.PkgPath...)
+	if err != nil {
+		return nil, err
+	}
+	if packages.PrintErrors(initial) > 0 {
+		return nil, errors.New("errors in loaded packages")
+	}
+	prog, all := ssautil.AllPackages(initial, ssa.SanityCheckFunctions)
+	prog.Build()
+	pkgs := make([]*ssa.Package, 0)
+	for _, pkg := range all {
+		if pkg != nil {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs, nil
+}
 
+// solve solves an analysis on a function, tracing every step if needed
+func (r *Runner) solve(f *ssa.Function) *ConstantPropagationAnalysis {
 	// Build analysis
-	analysis := New(graph)
+	analysis := New(graph.New(f))
+	analysis.GraphDstDir = r.GraphDstDir
+	analysis.Sparse = r.Sparse
+
+	// Solve analysis, trace every step if needed
+	doAnalysis(analysis, true, r.TraceDstDir)
+	return analysis
+}
 
-	// Solve analysis
-	solver.Solve(analysis, true)
+// doAnalysis solves an analysis and saves the trace of every step to traceDstDir if it is set
+func doAnalysis(analysis *ConstantPropagationAnalysis, debug bool, traceDstDir string) {
+	s := &solver.Solver{Analysis: analysis, Debug: debug}
+	if traceDstDir != "" {
+		s.Trace = solver.NewTrace(analysis.Graph)
+	}
+	s.DoAnalysis()
+
+	if s.Trace != nil {
+		err := s.Trace.Persist(traceDstDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// isTarget returns whether a function or its enclosing function is selected by Function or Functions
+// a function is selected by its name or full name, nothing selected means all functions
+func (r *Runner) isTarget(f *ssa.Function) bool {
+	if r.Function == "" && len(r.Functions) == 0 {
+		return true
+	}
+	for ; f != nil; f = f.Parent() {
+		for _, name := range append([]string{r.Function}, r.Functions...) {
+			if name != "" && (f.Name() == name || f.String() == name) {
+				return true
+			}
+		}
+	}
+	return false
 }
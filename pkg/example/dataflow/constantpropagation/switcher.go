@@ -1,8 +1,8 @@
 package constantpropagation
 
 import (
+	"go/constant"
 	"go/token"
-	"math"
 
 	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
 	"golang.org/x/tools/go/ssa"
@@ -18,101 +18,161 @@ type ConstantPropagationSwitcher struct {
 
 // CaseBinOp accepts a BinOp instruction
 func (s *ConstantPropagationSwitcher) CaseBinOp(inst *ssa.BinOp) {
-	v, u, n := s.lookup(inst.X)
-	v2, u2, n2 := s.lookup(inst.Y)
-	if !u && !u2 && !n && !n2 {
-		res, ok := s.evalBinOp(v, v2, inst.Op)
-		if ok {
-			(*s.outMap)[inst.Name()] = res
-		} else {
-			(*s.outMap)[inst.Name()] = "NAC"
+	x, y := s.lookup(inst.X), s.lookup(inst.Y)
+	if x == NAC || y == NAC {
+		(*s.outMap)[inst.Name()] = NAC
+	} else if x == UNDEF || y == UNDEF {
+		(*s.outMap)[inst.Name()] = UNDEF
+	} else if res, ok := evalBinOp(inst.Op, x.(constant.Value), y.(constant.Value), inst.X.Type(), inst.Type()); ok {
+		(*s.outMap)[inst.Name()] = res
+	} else {
+		(*s.outMap)[inst.Name()] = NAC
+	}
+}
+
+// CaseUnOp accepts a UnOp instruction
+// loads and receives are not constants, except loads of constant globals in interprocedural mode
+func (s *ConstantPropagationSwitcher) CaseUnOp(inst *ssa.UnOp) {
+	if g, ok := inst.X.(*ssa.Global); ok && inst.Op == token.MUL {
+		if ip := s.constanctPropagationAnalysis.interprocedural; ip != nil && !isInit(inst.Parent()) {
+			(*s.outMap)[inst.Name()] = ip.global(g)
+			return
 		}
-	} else if n || n2 {
-		(*s.outMap)[inst.Name()] = "NAC"
+	}
+	x := s.lookup(inst.X)
+	if x == UNDEF {
+		(*s.outMap)[inst.Name()] = UNDEF
+	} else if x == NAC {
+		(*s.outMap)[inst.Name()] = NAC
+	} else if res, ok := evalUnOp(inst.Op, x.(constant.Value), inst.Type()); ok {
+		(*s.outMap)[inst.Name()] = res
 	} else {
-		(*s.outMap)[inst.Name()] = "UNDEF"
+		(*s.outMap)[inst.Name()] = NAC
 	}
 }
 
-// CasePhi accepts a Phi instruction
-func (s *ConstantPropagationSwitcher) CasePhi(inst *ssa.Phi) {
-	for _, v := range inst.Edges {
-		switch v := (*s.outMap)[v.Name()].(type) {
-		case string:
-			if v == "NAC" {
-				(*s.outMap)[inst.Name()] = "NAC"
+// CaseConvert accepts a Convert instruction
+func (s *ConstantPropagationSwitcher) CaseConvert(inst *ssa.Convert) {
+	x := s.lookup(inst.X)
+	if x == UNDEF {
+		(*s.outMap)[inst.Name()] = UNDEF
+	} else if x == NAC {
+		(*s.outMap)[inst.Name()] = NAC
+	} else if res, ok := convert(x.(constant.Value), inst.Type()); ok {
+		(*s.outMap)[inst.Name()] = res
+	} else {
+		(*s.outMap)[inst.Name()] = NAC
+	}
+}
+
+// CaseChangeType accepts a ChangeType instruction
+func (s *ConstantPropagationSwitcher) CaseChangeType(inst *ssa.ChangeType) {
+	(*s.outMap)[inst.Name()] = s.lookup(inst.X)
+}
+
+// CaseCall accepts a Call instruction
+// the result of a static call is the summary of the callee in interprocedural mode
+func (s *ConstantPropagationSwitcher) CaseCall(inst *ssa.Call) {
+	if ip := s.constanctPropagationAnalysis.interprocedural; ip != nil {
+		if callee := inst.Call.StaticCallee(); callee != nil && isBasic(inst.Type()) {
+			(*s.outMap)[inst.Name()] = ip.result(callee, 0)
+			return
+		}
+	}
+	s.unknown(inst)
+}
+
+// CaseExtract accepts an Extract instruction
+func (s *ConstantPropagationSwitcher) CaseExtract(inst *ssa.Extract) {
+	if ip := s.constanctPropagationAnalysis.interprocedural; ip != nil {
+		if call, ok := inst.Tuple.(*ssa.Call); ok {
+			if callee := call.Call.StaticCallee(); callee != nil && isBasic(inst.Type()) {
+				(*s.outMap)[inst.Name()] = ip.result(callee, inst.Index)
 				return
 			}
 		}
 	}
-	allsame := true
-	counti := 0
-	i := 0
-	for _, v := range inst.Edges {
-		switch v := (*s.outMap)[v.Name()].(type) {
-		case int:
-			if counti == 0 {
-				counti++
-				i = v
-			} else {
-				if v != i {
-					counti++
-					allsame = false
-				}
-			}
+	s.unknown(inst)
+}
+
+// CasePhi accepts a Phi instruction
+// the result is the meet of all edges, only executable edges are met in sparse mode
+func (s *ConstantPropagationSwitcher) CasePhi(inst *ssa.Phi) {
+	var res any = UNDEF
+	b := inst.Block()
+	for i, v := range inst.Edges {
+		if s.constanctPropagationAnalysis.Sparse && (*s.outMap)[edgeKey(b.Preds[i], b)] != true {
+			continue
+		}
+		res = meet(res, s.lookup(v))
+	}
+	(*s.outMap)[inst.Name()] = res
+}
+
+// CaseIf accepts an If instruction
+// in sparse mode, only the edge selected by a constant condition is executable
+func (s *ConstantPropagationSwitcher) CaseIf(inst *ssa.If) {
+	if !s.constanctPropagationAnalysis.Sparse {
+		return
+	}
+	b := inst.Block()
+	switch c := s.lookup(inst.Cond).(type) {
+	case constant.Value:
+		if constant.BoolVal(c) {
+			(*s.outMap)[edgeKey(b, b.Succs[0])] = true
+		} else {
+			(*s.outMap)[edgeKey(b, b.Succs[1])] = true
 		}
-		if allsame == false {
-			(*s.outMap)[inst.Name()] = "NAC"
+	case string:
+		if c == NAC {
+			(*s.outMap)[edgeKey(b, b.Succs[0])] = true
+			(*s.outMap)[edgeKey(b, b.Succs[1])] = true
 		}
 	}
-	if counti == 0 {
-		(*s.outMap)[inst.Name()] = "UNDEF"
-	} else {
-		(*s.outMap)[inst.Name()] = i
+}
+
+// CaseJump accepts a Jump instruction
+func (s *ConstantPropagationSwitcher) CaseJump(inst *ssa.Jump) {
+	if !s.constanctPropagationAnalysis.Sparse {
+		return
 	}
+	b := inst.Block()
+	(*s.outMap)[edgeKey(b, b.Succs[0])] = true
 }
 
-func (s *ConstantPropagationSwitcher) lookup(_v ssa.Value) (int, bool, bool) {
-	switch v := (_v).(type) {
+// lookup returns the fact of a value
+// values which are not basic, free variables and globals are NAC,
+// parameters are NAC unless they are summarized in interprocedural mode
+func (s *ConstantPropagationSwitcher) lookup(v ssa.Value) any {
+	if !isBasic(v.Type()) {
+		return NAC
+	}
+	switch v := v.(type) {
 	case *ssa.Const:
-		return int(v.Int64()), false, false
-	default:
-		_r := (*s.outMap)[v.Name()]
-		switch r := (_r).(type) {
-		case string:
-			if r == "UNDEF" {
-				return 0, true, false
-			}
-			return 0, false, true
-		case int:
-			return r, false, false
+		if v.Value == nil {
+			return NAC
+		}
+		if res, ok := represent(v.Value, v.Type()); ok {
+			return res
 		}
+		return v.Value
+	case *ssa.Parameter:
+		if ip := s.constanctPropagationAnalysis.interprocedural; ip != nil {
+			return ip.param(v)
+		}
+		return NAC
+	case *ssa.FreeVar, *ssa.Global, *ssa.Function, *ssa.Builtin:
+		return NAC
+	}
+	if res, ok := (*s.outMap)[v.Name()]; ok {
+		return res
 	}
-	return 0, false, false
+	return UNDEF
 }
 
-func (s *ConstantPropagationSwitcher) evalBinOp(x int, y int, op token.Token) (int, bool) {
-	switch op {
-	case token.ADD:
-		return x + y, true
-	case token.SUB:
-		return x - y, true
-	case token.MUL:
-		return x * y, true
-	case token.QUO:
-		return x / y, true
-	case token.REM:
-		return x % y, true
-	case token.AND:
-		return x & y, true
-	case token.OR:
-		return x | y, true
-	case token.XOR:
-		return x ^ y, true
-	case token.SHL:
-		return x / int(math.Pow(2, float64(y))), true
-	case token.SHR:
-		return x * int(math.Pow(2, float64(y))), true
+// unknown marks a value of basic type as NAC
+func (s *ConstantPropagationSwitcher) unknown(v ssa.Value) {
+	if isBasic(v.Type()) {
+		(*s.outMap)[v.Name()] = NAC
 	}
-	return 0, false
 }
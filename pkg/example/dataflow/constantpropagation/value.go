@@ -0,0 +1,231 @@
+package constantpropagation
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// A fact in a flow is either UNDEF, NAC or a constant.Value
+// a value missing in a flow is UNDEF
+const (
+	UNDEF = "UNDEF"
+	NAC   = "NAC"
+)
+
+// reachableKey is the key of reachability of a program point in sparse mode
+const reachableKey = "@reachable"
+
+// edgeKey returns the key of an executable edge in sparse mode
+func edgeKey(from *ssa.BasicBlock, to *ssa.BasicBlock) string {
+	return fmt.Sprintf("@edge:%d->%d", from.Index, to.Index)
+}
+
+// sizes decides the size of int, uint and uintptr
+var sizes = types.SizesFor("gc", "amd64")
+
+// meet returns the meet of two facts
+func meet(x any, y any) any {
+	if x == NAC || y == NAC {
+		return NAC
+	}
+	if x == UNDEF {
+		return y
+	}
+	if y == UNDEF {
+		return x
+	}
+	if equal(x.(constant.Value), y.(constant.Value)) {
+		return x
+	}
+	return NAC
+}
+
+// equal returns whether two constants are the same
+func equal(x constant.Value, y constant.Value) bool {
+	return x.Kind() == y.Kind() && constant.Compare(x, token.EQL, y)
+}
+
+// evalBinOp evaluates x op y, t is the type of x and res is the type of the result
+// it fails if the operation panics at runtime or its result is not a constant
+func evalBinOp(op token.Token, x constant.Value, y constant.Value, t types.Type, res types.Type) (constant.Value, bool) {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if x.Kind() != y.Kind() || (x.Kind() == constant.Complex && op != token.EQL && op != token.NEQ) {
+			return nil, false
+		}
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+	case token.SHL, token.SHR:
+		s, ok := constant.Uint64Val(constant.ToInt(y))
+		if !ok || x.Kind() != constant.Int {
+			// a negative shift count panics
+			return nil, false
+		}
+		// shifting more than the size of a type gives the same result
+		if s > 128 {
+			s = 128
+		}
+		return represent(constant.Shift(x, op, uint(s)), res)
+	case token.QUO, token.REM:
+		if constant.Sign(y) == 0 {
+			// an integer division by zero panics and a float division by zero is not a constant
+			return nil, false
+		}
+		if op == token.QUO && isInteger(t) {
+			op = token.QUO_ASSIGN
+		}
+	}
+	if x.Kind() != y.Kind() {
+		return nil, false
+	}
+	return represent(constant.BinaryOp(x, op, y), res)
+}
+
+// evalUnOp evaluates op x, t is the type of x and the result
+func evalUnOp(op token.Token, x constant.Value, t types.Type) (constant.Value, bool) {
+	switch op {
+	case token.NOT, token.SUB:
+		return represent(constant.UnaryOp(op, x, 0), t)
+	case token.XOR:
+		prec := uint(0)
+		if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsUnsigned != 0 {
+			prec = uint(sizes.Sizeof(b) * 8)
+		}
+		return represent(constant.UnaryOp(op, x, prec), t)
+	}
+	return nil, false
+}
+
+// convert converts x to type t like a Convert instruction
+func convert(x constant.Value, t types.Type) (constant.Value, bool) {
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil, false
+	}
+	info := b.Info()
+	switch {
+	case info&types.IsString != 0:
+		switch x.Kind() {
+		case constant.String:
+			return x, true
+		case constant.Int:
+			// string(rune(x)), an invalid code point converts to "�"
+			r, ok := constant.Int64Val(x)
+			if !ok || r < 0 || r > utf8.MaxRune {
+				r = utf8.RuneError
+			}
+			return constant.MakeString(string(rune(r))), true
+		}
+	case info&types.IsInteger != 0:
+		switch x.Kind() {
+		case constant.Int:
+			return represent(x, t)
+		case constant.Float:
+			// a float is truncated towards zero, the result is implementation-specific if it overflows
+			f, _ := constant.Float64Val(x)
+			i := constant.ToInt(constant.MakeFloat64(math.Trunc(f)))
+			if i.Kind() != constant.Int {
+				return nil, false
+			}
+			if v, ok := represent(i, t); ok && equal(v, i) {
+				return v, true
+			}
+		}
+	case info&types.IsFloat != 0:
+		return represent(constant.ToFloat(x), t)
+	case info&types.IsComplex != 0:
+		return represent(constant.ToComplex(x), t)
+	case info&types.IsBoolean != 0:
+		if x.Kind() == constant.Bool {
+			return x, true
+		}
+	}
+	return nil, false
+}
+
+// represent returns the value x has in type t
+// integers wrap around and floats are rounded to the precision of t, an infinite float is not a constant
+func represent(x constant.Value, t types.Type) (constant.Value, bool) {
+	if x.Kind() == constant.Unknown {
+		return nil, false
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil, false
+	}
+	info := b.Info()
+	if info&types.IsUntyped != 0 {
+		return x, true
+	}
+	switch {
+	case info&types.IsInteger != 0:
+		if x.Kind() != constant.Int {
+			return nil, false
+		}
+		return wrap(x, uint(sizes.Sizeof(b)*8), info&types.IsUnsigned == 0), true
+	case info&types.IsFloat != 0:
+		return round(x, b.Kind() == types.Float32)
+	case info&types.IsComplex != 0:
+		re, ok := round(constant.Real(x), b.Kind() == types.Complex64)
+		if !ok {
+			return nil, false
+		}
+		im, ok := round(constant.Imag(x), b.Kind() == types.Complex64)
+		if !ok {
+			return nil, false
+		}
+		return constant.BinaryOp(re, token.ADD, constant.MakeImag(im)), true
+	}
+	return x, true
+}
+
+// wrap wraps an integer around to the given bits in two's complement
+func wrap(x constant.Value, bits uint, signed bool) constant.Value {
+	one := constant.MakeInt64(1)
+	mod := constant.Shift(one, token.SHL, bits)
+	x = constant.BinaryOp(x, token.AND, constant.BinaryOp(mod, token.SUB, one))
+	if signed && constant.Compare(x, token.GEQ, constant.Shift(one, token.SHL, bits-1)) {
+		x = constant.BinaryOp(x, token.SUB, mod)
+	}
+	return x
+}
+
+// round rounds a float to float32 or float64
+func round(x constant.Value, single bool) (constant.Value, bool) {
+	if single {
+		f, _ := constant.Float32Val(x)
+		if math.IsInf(float64(f), 0) {
+			return nil, false
+		}
+		return constant.MakeFloat64(float64(f)), true
+	}
+	f, _ := constant.Float64Val(x)
+	if math.IsInf(f, 0) {
+		return nil, false
+	}
+	return constant.MakeFloat64(f), true
+}
+
+// isBasic returns whether t is a basic type
+// only a basic type or a type with a name, like a named type or an alias, is asked for its underlying type,
+// because some types in ssa, like the type of a Range, are opaque and have no underlying type
+func isBasic(t types.Type) bool {
+	switch t.(type) {
+	case *types.Basic:
+		return true
+	case interface{ Obj() *types.TypeName }:
+		_, ok := t.Underlying().(*types.Basic)
+		return ok
+	}
+	return false
+}
+
+// isInteger returns whether t is an integer type
+func isInteger(t types.Type) bool {
+	return isBasic(t) && t.Underlying().(*types.Basic).Info()&types.IsInteger != 0
+}
@@ -0,0 +1,116 @@
+package expression
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Expression represents an expression computed by a BinOp or UnOp instruction
+// expressions are keyed by text of their operands, so the same computation in different instructions has the same key
+type Expression struct {
+	Key      string
+	Operands []string
+	IsLoad   bool
+}
+
+// Of returns the Expression an instruction computes, or nil if it computes none
+// receiving from a channel is not an expression because it has side effects
+func Of(inst ssa.Instruction) *Expression {
+	switch inst := inst.(type) {
+	case *ssa.BinOp:
+		x, y := operand(inst.X), operand(inst.Y)
+		if isCommutative(inst) && y < x {
+			x, y = y, x
+		}
+		return &Expression{Key: x + " " + inst.Op.String() + " " + y, Operands: operands(inst.X, inst.Y)}
+	case *ssa.UnOp:
+		if inst.Op == token.ARROW {
+			return nil
+		}
+		return &Expression{Key: inst.Op.String() + operand(inst.X), Operands: operands(inst.X), IsLoad: inst.Op == token.MUL}
+	}
+	return nil
+}
+
+// Collect returns all expressions computed in a function, indexed by their keys
+func Collect(f *ssa.Function) map[string]*Expression {
+	expressions := make(map[string]*Expression)
+	for _, b := range f.Blocks {
+		for _, inst := range b.Instrs {
+			if e := Of(inst); e != nil {
+				expressions[e.Key] = e
+			}
+		}
+	}
+	return expressions
+}
+
+// Kills returns whether an instruction kills an expression
+// an instruction defining an operand kills the expression,
+// without alias information, every store and call kills all loads
+func Kills(inst ssa.Instruction, e *Expression) bool {
+	if v, ok := inst.(ssa.Value); ok {
+		for _, o := range e.Operands {
+			if o == operand(v) {
+				return true
+			}
+		}
+	}
+	if e.IsLoad {
+		switch inst.(type) {
+		case *ssa.Store, *ssa.Call, *ssa.Go, *ssa.RunDefers:
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns sorted keys of expressions in a flow
+func Keys(flow *map[any]any) []string {
+	keys := make([]string, 0)
+	for k := range *flow {
+		keys = append(keys, k.(string))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// operand returns text of an operand
+func operand(v ssa.Value) string {
+	switch v := v.(type) {
+	case *ssa.Global:
+		return v.String()
+	case *ssa.Function:
+		return v.String()
+	}
+	return v.Name()
+}
+
+// operands returns text of operands which can be redefined, constants are never redefined
+func operands(values ...ssa.Value) []string {
+	res := make([]string, 0)
+	for _, v := range values {
+		if _, ok := v.(*ssa.Const); !ok {
+			res = append(res, operand(v))
+		}
+	}
+	return res
+}
+
+// isCommutative returns whether operands of a BinOp can be swapped
+func isCommutative(inst *ssa.BinOp) bool {
+	switch inst.Op {
+	case token.ADD:
+		// concatenation of strings is not commutative
+		if b, ok := inst.X.Type().Underlying().(*types.Basic); ok {
+			return b.Info()&types.IsString == 0
+		}
+		return false
+	case token.MUL, token.AND, token.OR, token.XOR, token.EQL, token.NEQ:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package reachingdefinition
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/scalar"
+	"github.com/cokeBeer/goot/pkg/dataflow/util/entry"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ReachingDefinitionAnalysis represents a reaching definitions analysis on memory locations
+// a flow maps a Location to ids of definitions reaching it, a missing Location is defined by its parent
+type ReachingDefinitionAnalysis struct {
+	scalar.BaseFlowAnalysis
+	reachingDefinitionSwitcher *ReachingDefinitionSwitcher
+	defs                       []ssa.Instruction
+	defIndex                   map[ssa.Instruction]int
+	locations                  map[string]Location
+	Chains                     []*Chain
+}
+
+// Chain represents a def-use chain of a memory location
+// Defs is empty if the location is only defined outside the function
+type Chain struct {
+	Function string
+	Use      string
+	Position string
+	Location string
+	Defs     []*Def
+}
+
+// Def represents a definition of a memory location
+// Kind is alloc for zero values, store for strong and weak stores, and call for locations passed to a callee
+type Def struct {
+	Instruction string
+	Position    string
+	Kind        string
+}
+
+// New creates a ReachingDefinitionAnalysis
+func New(g *graph.UnitGraph) *ReachingDefinitionAnalysis {
+	reachingDefinitionAnalysis := new(ReachingDefinitionAnalysis)
+	reachingDefinitionAnalysis.BaseFlowAnalysis = *scalar.NewBase(g)
+	reachingDefinitionAnalysis.defs = make([]ssa.Instruction, 0)
+	reachingDefinitionAnalysis.defIndex = make(map[ssa.Instruction]int)
+	reachingDefinitionAnalysis.locations = make(map[string]Location)
+	reachingDefinitionAnalysis.Chains = make([]*Chain, 0)
+	reachingDefinitionSwitcher := new(ReachingDefinitionSwitcher)
+	reachingDefinitionSwitcher.BaseSwitcher = *new(switcher.BaseSwitcher)
+	reachingDefinitionAnalysis.reachingDefinitionSwitcher = reachingDefinitionSwitcher
+	reachingDefinitionSwitcher.reachingDefinitionAnalysis = reachingDefinitionAnalysis
+	return reachingDefinitionAnalysis
+}
+
+// FlowThrougth calculate outMap based on inMap and unit
+func (a *ReachingDefinitionAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.Instruction, outMap *map[any]any) {
+	a.Copy(inMap, outMap)
+	a.apply(inMap, unit, outMap)
+}
+
+// MergeInto merge from in to inout based on unit
+// definitions of a location are unioned, a location missing in one flow is looked up from its parent
+func (a *ReachingDefinitionAnalysis) MergeInto(unit ssa.Instruction, inout *map[any]any, in *map[any]any) {
+	keys := make(map[string]bool)
+	for k := range *inout {
+		keys[k.(string)] = true
+	}
+	for k := range *in {
+		keys[k.(string)] = true
+	}
+	merged := make(map[string][]int)
+	for k := range keys {
+		l := a.locations[k]
+		merged[k] = union(a.reaching(inout, l), a.reaching(in, l))
+	}
+	for k, v := range merged {
+		(*inout)[k] = v
+	}
+}
+
+// End handle result of analysis
+// every load of a memory location is recorded as a Chain
+func (a *ReachingDefinitionAnalysis) End(universe []*entry.Entry) {
+	f := a.Graph.Func
+	for _, v := range universe {
+		load, ok := v.Data.(*ssa.UnOp)
+		if !ok || load.Op != token.MUL {
+			continue
+		}
+		l, _ := a.location(load.X)
+		chain := &Chain{Function: f.String(), Use: graph.InstructionString(load),
+			Position: a.position(load), Location: l.String(), Defs: make([]*Def, 0)}
+		for _, id := range a.uses(v.InFlow, l) {
+			def := a.defs[id]
+			chain.Defs = append(chain.Defs, &Def{Instruction: graph.InstructionString(def),
+				Position: a.position(def), Kind: kindOf(def)})
+		}
+		a.Chains = append(a.Chains, chain)
+	}
+}
+
+func (a *ReachingDefinitionAnalysis) apply(inMap *map[any]any, inst ssa.Instruction, outMap *map[any]any) {
+	a.reachingDefinitionSwitcher.inMap = inMap
+	a.reachingDefinitionSwitcher.outMap = outMap
+	switcher.Apply(a.reachingDefinitionSwitcher, inst)
+}
+
+// define returns the id of a definition
+func (a *ReachingDefinitionAnalysis) define(inst ssa.Instruction) int {
+	if id, ok := a.defIndex[inst]; ok {
+		return id
+	}
+	id := len(a.defs)
+	a.defs = append(a.defs, inst)
+	a.defIndex[inst] = id
+	return id
+}
+
+// location returns the Location an address refers to and whether a store to it is a strong update
+// the Location is recorded so that MergeInto can look up its parent
+func (a *ReachingDefinitionAnalysis) location(addr ssa.Value) (Location, bool) {
+	l, strong := locationOf(addr)
+	a.locations[l.String()] = l
+	return l, strong
+}
+
+// reaching returns definitions reaching a location in a flow
+// a location without its own definitions is defined by the wildcard element or the parent it is selected from
+func (a *ReachingDefinitionAnalysis) reaching(flow *map[any]any, l Location) []int {
+	if defs, ok := (*flow)[l.String()]; ok {
+		return defs.([]int)
+	}
+	if len(l) == 1 {
+		return nil
+	}
+	if l.isIndex() && !l.isWildcard() {
+		if defs, ok := (*flow)[l.parent().String()+wildcard]; ok {
+			return defs.([]int)
+		}
+	}
+	return a.reaching(flow, l.parent())
+}
+
+// uses returns definitions a load of a location can see, which includes definitions of its sub-locations
+// a load of a wildcard element can also see definitions of every constant element
+func (a *ReachingDefinitionAnalysis) uses(flow *map[any]any, l Location) []int {
+	defs := a.reaching(flow, l)
+	prefixes := []string{l.String() + ".", l.String() + "["}
+	if l.isWildcard() {
+		prefixes = append(prefixes, l.parent().String()+"[")
+	}
+	for k, v := range *flow {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(k.(string), prefix) {
+				defs = union(defs, v.([]int))
+				break
+			}
+		}
+	}
+	return defs
+}
+
+// position returns the position of an instruction in string
+func (a *ReachingDefinitionAnalysis) position(inst ssa.Instruction) string {
+	f := a.Graph.Func
+	if pos := inst.Pos(); pos.IsValid() && f.Prog != nil {
+		return f.Prog.Fset.Position(pos).String()
+	}
+	return ""
+}
+
+// kindOf returns the kind of a definition
+func kindOf(inst ssa.Instruction) string {
+	switch inst.(type) {
+	case *ssa.Alloc:
+		return "alloc"
+	case *ssa.Store:
+		return "store"
+	}
+	return "call"
+}
+
+// union returns the sorted union of two sets of definition ids without modifying them
+func union(x []int, y []int) []int {
+	seen := make(map[int]bool)
+	res := make([]int, 0, len(x)+len(y))
+	for _, v := range x {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	for _, v := range y {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	sort.Ints(res)
+	return res
+}
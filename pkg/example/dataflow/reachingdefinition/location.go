@@ -0,0 +1,62 @@
+package reachingdefinition
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// wildcard represents an element selected by a non-constant index
+const wildcard = "[*]"
+
+// Location represents an addressable memory location
+// it is a root followed by field and index selectors, like ["t0", ".Name"] or ["t3", "[1]"]
+type Location []string
+
+// String returns the key of a Location in a flow
+func (l Location) String() string {
+	return strings.Join(l, "")
+}
+
+// parent returns the Location that l is selected from
+func (l Location) parent() Location {
+	return l[:len(l)-1]
+}
+
+// isWildcard returns whether l selects an element by a non-constant index
+func (l Location) isWildcard() bool {
+	return len(l) > 1 && l[len(l)-1] == wildcard
+}
+
+// isIndex returns whether l selects an element by an index
+func (l Location) isIndex() bool {
+	return len(l) > 1 && strings.HasPrefix(l[len(l)-1], "[")
+}
+
+// locationOf returns the Location an address refers to, and whether a store to it is a strong update
+// locations are named by the value they are selected from, so aliasing between different pointers is not considered
+func locationOf(addr ssa.Value) (Location, bool) {
+	switch v := addr.(type) {
+	case *ssa.Alloc:
+		return Location{v.Name()}, true
+	case *ssa.Global:
+		return Location{v.String()}, true
+	case *ssa.FieldAddr:
+		base, strong := locationOf(v.X)
+		return append(base[:len(base):len(base)], "."+fieldName(v)), strong
+	case *ssa.IndexAddr:
+		base, strong := locationOf(v.X)
+		if c, ok := v.Index.(*ssa.Const); ok {
+			return append(base[:len(base):len(base)], "["+c.Value.String()+"]"), strong
+		}
+		return append(base[:len(base):len(base)], wildcard), false
+	}
+	return Location{addr.Name()}, true
+}
+
+// fieldName returns the name of the field a FieldAddr selects
+func fieldName(v *ssa.FieldAddr) string {
+	t := v.X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct)
+	return t.Field(v.Field).Name()
+}
@@ -0,0 +1,106 @@
+package reachingdefinition
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/solver"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Runner represents a reaching definitions runner
+type Runner struct {
+	Src      string
+	Function string
+	DstPath  string
+	Chains   []*Chain
+}
+
+// NewRunner returns a Runner, an empty function means all functions in src
+func NewRunner(src string, function string) *Runner {
+	runner := new(Runner)
+	runner.Src = src
+	runner.Function = function
+	runner.Chains = make([]*Chain, 0)
+	return runner
+}
+
+// Run kick off the analysis
+func (r *Runner) Run() error {
+	// Generate ast
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", r.Src, parser.Mode(0))
+	if err != nil {
+		return err
+	}
+	files := []*ast.File{f}
+
+	// Build package
+	pkg := types.NewPackage("reachingdefinitionanalysis", "")
+	hello, _, err := ssautil.BuildPackage(
+		&types.Config{Importer: importer.Default()}, fset, pkg, files, ssa.SanityCheckFunctions)
+	if err != nil {
+		return err
+	}
+
+	// Solve analysis on every target function
+	for _, fn := range r.functions(hello) {
+		analysis := New(graph.New(fn))
+		solver.Solve(analysis, false)
+		r.Chains = append(r.Chains, analysis.Chains...)
+	}
+
+	// Write def-use chains in json format
+	res, err := json.MarshalIndent(r.Chains, "", "    ")
+	if err != nil {
+		return err
+	}
+	if r.DstPath == "" {
+		_, err = os.Stdout.Write(append(res, '\n'))
+		return err
+	}
+	return os.WriteFile(r.DstPath, res, 0666)
+}
+
+// functions returns functions with body to analyze, including anonymous functions
+func (r *Runner) functions(pkg *ssa.Package) []*ssa.Function {
+	funcs := make([]*ssa.Function, 0)
+	var add func(f *ssa.Function)
+	add = func(f *ssa.Function) {
+		if f.Blocks != nil {
+			funcs = append(funcs, f)
+		}
+		for _, anon := range f.AnonFuncs {
+			add(anon)
+		}
+	}
+	if r.Function != "" {
+		f := pkg.Func(r.Function)
+		if f == nil {
+			log.Println("can't find function", r.Function)
+			return funcs
+		}
+		add(f)
+		return funcs
+	}
+	names := make([]string, 0)
+	for name := range pkg.Members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if f, ok := pkg.Members[name].(*ssa.Function); ok {
+			add(f)
+		}
+	}
+	return funcs
+}
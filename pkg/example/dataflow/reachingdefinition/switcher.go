@@ -0,0 +1,126 @@
+package reachingdefinition
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
+	"golang.org/x/tools/go/ssa"
+)
+
+// ReachingDefinitionSwitcher represents a reaching definitions switcher
+type ReachingDefinitionSwitcher struct {
+	switcher.BaseSwitcher
+	reachingDefinitionAnalysis *ReachingDefinitionAnalysis
+	inMap                      *map[any]any
+	outMap                     *map[any]any
+}
+
+// CaseAlloc accepts an Alloc instruction
+// an Alloc defines the zero value of the location
+func (s *ReachingDefinitionSwitcher) CaseAlloc(inst *ssa.Alloc) {
+	a := s.reachingDefinitionAnalysis
+	l, _ := a.location(inst)
+	s.kill(l, a.define(inst))
+}
+
+// CaseStore accepts a Store instruction
+func (s *ReachingDefinitionSwitcher) CaseStore(inst *ssa.Store) {
+	a := s.reachingDefinitionAnalysis
+	l, strong := a.location(inst.Addr)
+	if strong {
+		s.kill(l, a.define(inst))
+	} else {
+		s.gen(l, a.define(inst))
+	}
+}
+
+// CaseCall accepts a Call instruction
+func (s *ReachingDefinitionSwitcher) CaseCall(inst *ssa.Call) {
+	s.passArgs(inst, inst.Common())
+}
+
+// CaseGo accepts a Go instruction
+func (s *ReachingDefinitionSwitcher) CaseGo(inst *ssa.Go) {
+	s.passArgs(inst, inst.Common())
+}
+
+// CaseRunDefers accepts a RunDefers instruction
+// every deferred call of the function may run here
+func (s *ReachingDefinitionSwitcher) CaseRunDefers(inst *ssa.RunDefers) {
+	for _, b := range inst.Parent().Blocks {
+		for _, v := range b.Instrs {
+			if d, ok := v.(*ssa.Defer); ok {
+				s.passArgs(d, d.Common())
+			}
+		}
+	}
+}
+
+// passArgs weakly defines locations passed to a callee
+// a pointer argument may define the location it points to and a slice argument may define any of its elements,
+// variables captured by a closure are passed as pointers too, builtins other than copy define nothing
+func (s *ReachingDefinitionSwitcher) passArgs(inst ssa.Instruction, call *ssa.CallCommon) {
+	a := s.reachingDefinitionAnalysis
+	args := call.Args
+	switch v := call.Value.(type) {
+	case *ssa.Builtin:
+		if v.Name() != "copy" {
+			return
+		}
+		args = args[:1]
+	case *ssa.MakeClosure:
+		args = append(v.Bindings[:len(v.Bindings):len(v.Bindings)], args...)
+	}
+	for _, arg := range args {
+		switch arg.Type().Underlying().(type) {
+		case *types.Pointer:
+			l, _ := a.location(arg)
+			s.gen(l, a.define(inst))
+		case *types.Slice:
+			l, _ := a.location(arg)
+			l = append(l[:len(l):len(l)], wildcard)
+			a.locations[l.String()] = l
+			s.gen(l, a.define(inst))
+		}
+	}
+}
+
+// kill makes a definition the only one reaching a location and its sub-locations
+func (s *ReachingDefinitionSwitcher) kill(l Location, id int) {
+	defs := []int{id}
+	(*s.outMap)[l.String()] = defs
+	for _, k := range s.subLocations(l) {
+		(*s.outMap)[k] = defs
+	}
+}
+
+// gen adds a definition to a location and its sub-locations
+// a wildcard element also adds the definition to every constant element
+func (s *ReachingDefinitionSwitcher) gen(l Location, id int) {
+	a := s.reachingDefinitionAnalysis
+	defs := []int{id}
+	(*s.outMap)[l.String()] = union(a.reaching(s.inMap, l), defs)
+	for _, k := range s.subLocations(l) {
+		(*s.outMap)[k] = union((*s.inMap)[k].([]int), defs)
+	}
+	if l.isWildcard() {
+		prefix := l.parent().String() + "["
+		for k, v := range *s.inMap {
+			if strings.HasPrefix(k.(string), prefix) {
+				(*s.outMap)[k] = union(v.([]int), defs)
+			}
+		}
+	}
+}
+
+// subLocations returns keys of sub-locations of a location in inMap
+func (s *ReachingDefinitionSwitcher) subLocations(l Location) []string {
+	keys := make([]string, 0)
+	for k := range *s.inMap {
+		if strings.HasPrefix(k.(string), l.String()+".") || strings.HasPrefix(k.(string), l.String()+"[") {
+			keys = append(keys, k.(string))
+		}
+	}
+	return keys
+}
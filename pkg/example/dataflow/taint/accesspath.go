@@ -0,0 +1,245 @@
+package taint
+
+import (
+	"go/types"
+	"strings"
+)
+
+// FieldSeparator separates a name and an access path in a key of flow or a taint
+// e.g. t1->Header.Host is the key of field Host of field Header of t1,
+// and a taint p->Header.Host originates from the same field of parameter p
+const FieldSeparator = "->"
+
+// fieldKey returns the key of an access path under a name, an empty path is the name itself
+func fieldKey(name string, path string) string {
+	if path == "" {
+		return name
+	}
+	return name + FieldSeparator + path
+}
+
+// joinPath joins access paths and skips empty ones
+func joinPath(paths ...string) string {
+	fields := make([]string, 0)
+	for _, path := range paths {
+		if path != "" {
+			fields = append(fields, path)
+		}
+	}
+	return strings.Join(fields, ".")
+}
+
+// truncatePath keeps at most depth fields of an access path
+func truncatePath(path string, depth int) string {
+	if path == "" {
+		return path
+	}
+	fields := strings.Split(path, ".")
+	if len(fields) > depth {
+		fields = fields[:depth]
+	}
+	return strings.Join(fields, ".")
+}
+
+// splitPath splits an access path into its parent path and its last field
+func splitPath(path string) (string, string) {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
+
+// TaintRoot returns the name a taint originates from
+func TaintRoot(taint string) string {
+	if i := strings.Index(taint, FieldSeparator); i >= 0 {
+		return taint[:i]
+	}
+	return taint
+}
+
+// TaintPath returns the access path a taint originates from, an empty path is the whole name
+func TaintPath(taint string) string {
+	if i := strings.Index(taint, FieldSeparator); i >= 0 {
+		return taint[i+len(FieldSeparator):]
+	}
+	return ""
+}
+
+// refineTaint returns the taint of an access path under a taint, limited by depth
+func refineTaint(taint string, path string, depth int) string {
+	return fieldKey(TaintRoot(taint), truncatePath(joinPath(TaintPath(taint), path), depth))
+}
+
+// subPaths returns access paths of keys under an access path of a name in a flow, relative to the path
+func subPaths(flow *map[any]any, name string, path string) []string {
+	prefix := fieldKey(name, path)
+	if path == "" {
+		prefix += FieldSeparator
+	} else {
+		prefix += "."
+	}
+	paths := make([]string, 0)
+	for key := range *flow {
+		if k, ok := key.(string); ok && strings.HasPrefix(k, prefix) {
+			paths = append(paths, k[len(prefix):])
+		}
+	}
+	return paths
+}
+
+// dropFields deletes keys of access paths in outMap which are not in inMap
+// they are left by a former computation of outMap, and would not be overwritten by copying inMap
+func dropFields(inMap *map[any]any, outMap *map[any]any) {
+	for key := range *outMap {
+		if k, ok := key.(string); ok && strings.Contains(k, FieldSeparator) {
+			if _, ok := (*inMap)[key]; !ok {
+				delete(*outMap, key)
+			}
+		}
+	}
+}
+
+// lookupTaintWrapper returns wrapper with a key, or nil if the key is not in the flow
+func lookupTaintWrapper(flow *map[any]any, name string) *TaintWrapper {
+	if wrapper, ok := (*flow)[name]; ok {
+		return wrapper.(*TaintWrapper)
+	}
+	return nil
+}
+
+// depth returns the access path depth of the analysis, 0 means taint is field-insensitive
+func (s *TaintSwitcher) depth() int {
+	return s.taintAnalysis.config.AccessPathDepth
+}
+
+// readPath inherits taint flowing to an access path of a name into a wrapper
+// that is taint of the path, and taint of its ancestors which is refined by the rest of the path
+func (s *TaintSwitcher) readPath(w *TaintWrapper, name string, path string, removed ...string) {
+	if path == "" {
+		w.InheritTaint(s.outMap, name, removed...)
+		return
+	}
+	fields := strings.Split(path, ".")
+	for k := 0; k <= len(fields); k++ {
+		old := lookupTaintWrapper(s.outMap, fieldKey(name, strings.Join(fields[:k], ".")))
+		if old == nil {
+			continue
+		}
+		var rename func(string) string
+		if rest := strings.Join(fields[k:], "."); rest != "" {
+			rename = func(taint string) string {
+				return refineTaint(taint, rest, s.depth())
+			}
+		}
+		w.inheritWrapper(old, rename, removed...)
+	}
+}
+
+// copyPath passes taint of an access path of src and its fields to an access path of dst
+func (s *TaintSwitcher) copyPath(dst string, dstPath string, src string, srcPath string, removed ...string) {
+	subs := subPaths(s.outMap, src, srcPath)
+	s.readPath(GetTaintWrapper(s.outMap, fieldKey(dst, truncatePath(dstPath, s.depth()))), src, srcPath, removed...)
+	for _, sub := range subs {
+		key := fieldKey(dst, truncatePath(joinPath(dstPath, sub), s.depth()))
+		GetTaintWrapper(s.outMap, key).inheritWrapper(GetTaintWrapper(s.outMap, fieldKey(src, joinPath(srcPath, sub))), nil, removed...)
+	}
+}
+
+// passValue passes taint from src to dst which is a copy or an alias of src, including taint of its fields
+func (s *TaintSwitcher) passValue(dst string, src string) {
+	if s.depth() == 0 {
+		PassTaint(s.outMap, dst, src)
+		return
+	}
+	s.copyPath(dst, "", src, "")
+}
+
+// readField passes taint of a field of src to dst
+func (s *TaintSwitcher) readField(dst string, src string, field string) {
+	if s.depth() == 0 {
+		PassTaint(s.outMap, dst, src)
+		return
+	}
+	s.copyPath(dst, "", src, field)
+}
+
+// writeField passes taint of src to a field of dst
+func (s *TaintSwitcher) writeField(dst string, field string, src string) {
+	if s.depth() == 0 {
+		PassTaint(s.outMap, dst, src)
+		return
+	}
+	s.copyPath(dst, field, src, "")
+}
+
+// carriedTaint returns taint carried by a name and all of its fields
+func (s *TaintSwitcher) carriedTaint(name string) *TaintWrapper {
+	if s.depth() == 0 {
+		return GetTaintWrapper(s.outMap, name)
+	}
+	w := NewTaintWrapper()
+	w.InheritTaint(s.outMap, name)
+	for _, sub := range subPaths(s.outMap, name, "") {
+		w.InheritTaint(s.outMap, fieldKey(name, sub))
+	}
+	return w
+}
+
+// fieldName returns name of the i'th field of a struct or a pointer to struct
+func fieldName(typ types.Type, i int) string {
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	return typ.Underlying().(*types.Struct).Field(i).Name()
+}
+
+// returnFields passes taint of fields of a value to the i'th value at a position of passthrough
+func (s *TaintSwitcher) returnFields(position string, i int, name string) {
+	if s.depth() == 0 {
+		return
+	}
+	for _, sub := range subPaths(s.outMap, name, "") {
+		s.taintAnalysis.passThrough.Field(position, i, truncatePath(sub, s.depth())).InheritTaint(s.outMap, fieldKey(name, sub))
+	}
+}
+
+// inheritPassThrough inherits taint of an arg flowing to the i'th value at a position by passthrough from the j'th name
+// taint of the whole value is inherited into newTaint, and taint of its fields is saved into fields by access paths
+func (s *TaintSwitcher) inheritPassThrough(newTaint *TaintWrapper, fields map[string]*TaintWrapper, c *PassThroughCache, position string, i int, j int, arg string) {
+	removed := c.RemovedLabels(position, i, j)
+	if s.depth() == 0 {
+		newTaint.InheritTaint(s.outMap, arg, removed...)
+		return
+	}
+	flows, ok := c.Fields[RemovedKey(position, i, j)]
+	if !ok {
+		// a way without field flows is a flow between whole values
+		flows = []*FieldFlow{{}}
+	}
+	for _, flow := range flows {
+		to := truncatePath(flow.To, s.depth())
+		s.readPath(fieldWrapper(newTaint, fields, to), arg, flow.From, removed...)
+		for _, sub := range subPaths(s.outMap, arg, flow.From) {
+			w := fieldWrapper(newTaint, fields, truncatePath(joinPath(to, sub), s.depth()))
+			w.inheritWrapper(GetTaintWrapper(s.outMap, fieldKey(arg, joinPath(flow.From, sub))), nil, removed...)
+		}
+	}
+}
+
+// fieldWrapper returns newTaint for an empty access path, else the wrapper of the access path in fields
+func fieldWrapper(newTaint *TaintWrapper, fields map[string]*TaintWrapper, path string) *TaintWrapper {
+	if path == "" {
+		return newTaint
+	}
+	if _, ok := fields[path]; !ok {
+		fields[path] = NewTaintWrapper()
+	}
+	return fields[path]
+}
+
+// setFields sets taint of fields of a value by access paths
+func (s *TaintSwitcher) setFields(name string, fields map[string]*TaintWrapper) {
+	for path, w := range fields {
+		SetTaintWrapper(s.outMap, fieldKey(name, path), w)
+	}
+}
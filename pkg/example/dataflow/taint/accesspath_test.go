@@ -0,0 +1,58 @@
+package taint
+
+import "testing"
+
+const accessPathSrc = `package test
+
+type Header struct {
+	Host string
+	Path string
+}
+
+type Req struct {
+	Header Header
+	Name   string
+}
+
+func Cmd(s string) {}
+
+func SetHost(r *Req, v string) { r.Header.Host = v }
+
+func GetPath(r *Req) string { return r.Header.Path }
+
+func SrcField(a string) {
+	r := Req{Name: a}
+	Cmd(r.Header.Host)
+}
+
+func SrcSame(a string) {
+	r := &Req{}
+	r.Name = a
+	Cmd(r.Name)
+}
+
+func SrcNested(a string) {
+	r := &Req{}
+	r.Header.Host = a
+	Cmd(r.Header.Path)
+}
+
+func SrcCall(a string) {
+	r := &Req{}
+	SetHost(r, a)
+	Cmd(GetPath(r))
+}
+`
+
+func TestAccessPath(t *testing.T) {
+	depth := func(depth int) func(c *TaintConfig) {
+		return func(c *TaintConfig) { c.AccessPathDepth = depth }
+	}
+	// fields are not told apart without access paths
+	assertFindings(t, analyze(t, accessPathSrc, depth(0)),
+		"SrcCall#0->Cmd#0", "SrcField#0->Cmd#0", "SrcNested#0->Cmd#0", "SrcSame#0->Cmd#0")
+	// Header.Host and Header.Path are truncated to Header
+	assertFindings(t, analyze(t, accessPathSrc, depth(1)),
+		"SrcCall#0->Cmd#0", "SrcNested#0->Cmd#0", "SrcSame#0->Cmd#0")
+	assertFindings(t, analyze(t, accessPathSrc, depth(2)), "SrcSame#0->Cmd#0")
+}
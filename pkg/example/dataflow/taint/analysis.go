@@ -1,9 +1,10 @@
 package taint
 
 import (
-	"fmt"
 	"go/types"
+	"log"
 	"os"
+	"strconv"
 
 	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
 	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
@@ -19,9 +20,13 @@ type TaintAnalysis struct {
 	taintSwitcher *TaintSwitcher
 	passThrough   *PassThrough
 	config        *TaintConfig
+	context       *Context
+	controlDeps   [][]*ssa.If
 }
 
 // Run kicks off a taint analysis on a function
+// callees without a summary in passThroughContainer are treated as null passthrough,
+// so use a Scheduler to make sure callees are analyzed before their callers
 func Run(f *ssa.Function, c *TaintConfig) {
 	// if has recorded in passThroughContainer in somewhere else, skip
 	if _, ok := (*c.PassThroughContainer)[f.String()]; ok {
@@ -29,7 +34,7 @@ func Run(f *ssa.Function, c *TaintConfig) {
 	}
 
 	if needNull(f, c) {
-		// if the function is in recursive or has no body, init as null
+		// if the function has no body, init as null
 		initNull(f, c)
 		return
 	}
@@ -39,24 +44,31 @@ func Run(f *ssa.Function, c *TaintConfig) {
 	}
 
 	// else, do run an analysis on a *ssa.Function
-	doRun(f, c)
+	doRun(f, nil, c)
 }
 
-func doRun(f *ssa.Function, c *TaintConfig) {
-	// mark function as visited in history to inhibit recursive
-	recordCall(f, c)
-
+// doRun runs an analysis on a function in a context, a nil context means any context
+func doRun(f *ssa.Function, context *Context, c *TaintConfig) {
 	// create a new analysis
 	g := graph.New(f)
 	a := New(g, c)
+	a.context = context
+
+	// trace the target function if needed
+	var trace *solver.Trace
+	if c.TraceDstDir != "" && f.String() == c.TargetFunc && context == nil {
+		trace = solver.NewTrace(g)
+	}
 
 	// solve the analysis in debug mode
-	solver.Solve(a, c.Debug)
-}
+	s := &solver.Solver{Analysis: a, Debug: c.Debug, Trace: trace}
+	s.DoAnalysis()
 
-func recordCall(f *ssa.Function, c *TaintConfig) {
-	(*c.History)[f.String()] = true
-	c.CallStack.PushBack(f)
+	if trace != nil {
+		if err := trace.Persist(c.TraceDstDir); err != nil {
+			log.Println(err)
+		}
+	}
 }
 
 func initNull(f *ssa.Function, c *TaintConfig) {
@@ -70,39 +82,28 @@ func initNull(f *ssa.Function, c *TaintConfig) {
 	recv := f.Signature.Recv() != nil
 	result := f.Signature.Results().Len()
 	param := f.Signature.Params().Len()
+	if len(f.Params) == 0 {
+		// a function without body has no f.Params, so name params by their positions
+		n := param
+		if recv {
+			n++
+		}
+		for i := 0; i < n; i++ {
+			names = append(names, strconv.Itoa(i))
+		}
+	}
 	passThrough := NewPassThrough(names, recv, result, param)
 	passThroughCache := passThrough.ToCache()
+	passThroughCache.Sanitize(&Node{Function: f, Canonical: f.String()}, c.Ruler)
 	(*c.PassThroughContainer)[f.String()] = passThroughCache
-	fmt.Println("end analysis for:", f.String(), ", result: ", passThroughCache)
+	if c.Debug {
+		log.Println("end analysis for:", f.String(), ", result: ", passThroughCache)
+	}
 }
 
 func needNull(f *ssa.Function, c *TaintConfig) bool {
 	// is the function has no body?
-	if f.Blocks == nil {
-		return true
-	}
-
-	// is the function has marked as visited?
-	if _, ok := (*c.History)[f.String()]; ok {
-		caller := c.CallStack.Back().Value.(*ssa.Function)
-		IsCallerExported := false
-		IsCalleeExported := true
-		IsSamePackage := false
-		if caller.Object() != nil && caller.Object().Exported() {
-			IsCallerExported = true
-		}
-		if f.Object() != nil && !f.Object().Exported() {
-			IsCalleeExported = false
-		}
-		if caller.Pkg != nil && f.Pkg != nil && caller.Pkg.String() == f.Pkg.String() {
-			IsSamePackage = true
-		}
-		if IsCallerExported && !IsCalleeExported && IsSamePackage {
-			return false
-		}
-		return true
-	}
-	return false
+	return f.Blocks == nil
 }
 
 // New creates a TaintAnalysis
@@ -125,6 +126,10 @@ func New(g *graph.UnitGraph, c *TaintConfig) *TaintAnalysis {
 	param := f.Signature.Params().Len()
 
 	taintAnalysis.passThrough = NewPassThrough(names, recv, result, param)
+	taintAnalysis.passThrough.BindFreeVars(freeVarNames(f))
+	if c.ImplicitFlow {
+		taintAnalysis.controlDeps = controlDependence(f)
+	}
 	return taintAnalysis
 }
 
@@ -136,6 +141,10 @@ func (a *TaintAnalysis) NewInitalFlow() *map[any]any {
 		// init param taints in flow
 		SetTaint(&m, v.Name(), v.Name())
 	}
+	for _, v := range a.Graph.Func.FreeVars {
+		// init free variable taints in flow like params
+		SetTaint(&m, v.Name(), v.Name())
+	}
 	return &m
 }
 
@@ -146,6 +155,9 @@ func (a *TaintAnalysis) Computations() int {
 
 // FlowThrougth calculates outMap based on inMap and unit
 func (a *TaintAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.Instruction, outMap *map[any]any) {
+	if a.config.AccessPathDepth != 0 {
+		dropFields(inMap, outMap)
+	}
 	a.Copy(inMap, outMap)
 	a.apply(inMap, unit, outMap)
 }
@@ -155,6 +167,7 @@ func (a *TaintAnalysis) apply(inMap *map[any]any, inst ssa.Instruction, outMap *
 	a.taintSwitcher.inMap = inMap
 	a.taintSwitcher.outMap = outMap
 	switcher.Apply(a.taintSwitcher, inst)
+	a.taintSwitcher.passImplicitTaint(inst)
 }
 
 // MergeInto merges from in to inout based on unit
@@ -184,12 +197,19 @@ func (a *TaintAnalysis) End(universe []*entry.Entry) {
 		}
 	}
 
-	// save passThrough to passThroughContainer
-	passThroughCache := a.passThrough.ToCache()
-	(*c.PassThroughContainer)[f.String()] = passThroughCache
+	// export graph of the target function if needed
+	if c.GraphDstDir != "" && f.String() == c.TargetFunc && a.context == nil {
+		if err := graph.NewExporter(a.Graph, universe).Persist(c.GraphDstDir); err != nil {
+			log.Println(err)
+		}
+	}
 
-	// pop callStack
-	c.CallStack.Remove(c.CallStack.Back())
+	// save passThrough to passThroughContainer, a sanitizer's passthrough is sanitized
+	passThroughCache := a.passThrough.ToCache()
+	passThroughCache.Sanitize(&Node{Function: f, Canonical: f.String()}, c.Ruler)
+	(*c.PassThroughContainer)[ContextKey(f, a.context)] = passThroughCache
 
-	fmt.Println("finish analysis for: "+f.String()+", result: ", passThroughCache)
+	if c.Debug {
+		log.Println("finish analysis for: "+ContextKey(f, a.context)+", result: ", passThroughCache)
+	}
 }
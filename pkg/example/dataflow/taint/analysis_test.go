@@ -0,0 +1,115 @@
+package taint
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// testPkg is the path of packages built for tests
+const testPkg = "example.com/test"
+
+// testPack returns rules for tests
+// parameters of functions named like Src, results of Getenv and reads of Host of a Request are sources,
+// Cmd and Exec of a Shell are sinks and Clean sanitizes its results
+func testPack() *rule.Pack {
+	pack := rule.NewPack()
+	pack.Sources = append(pack.Sources, &rule.Rule{Regex: `^example\.com/test\.Src`})
+	result := rule.ResultIndex
+	pack.Sources = append(pack.Sources, &rule.Rule{Regex: `^(example\.com/test\.Getenv|\(example\.com/test\.Request\)\.Host)$`, Index: &result})
+	pack.Sinks = append(pack.Sinks, &rule.Rule{Function: testPkg + ".Cmd", Category: rule.CmdI})
+	pack.Sinks = append(pack.Sinks, &rule.Rule{Function: "(" + testPkg + ".Shell).Exec", Category: rule.CmdI})
+	pack.Sanitizers = append(pack.Sanitizers, &rule.Rule{Function: testPkg + ".Clean"})
+	return pack
+}
+
+// analyze builds a package from a source string and runs taint analysis on all of its functions
+// set changes the config before analysis, like options of a Runner
+func analyze(t *testing.T, src string, set func(c *TaintConfig)) *TaintConfig {
+	t.Helper()
+	return analyzePackage(build(t, src), set)
+}
+
+// build builds a package from a source string
+func build(t *testing.T, src string) *ssa.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "test.go", src, parser.Mode(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{Importer: importer.Default()}, fset,
+		types.NewPackage(testPkg, ""), []*ast.File{f}, ssa.SanityCheckFunctions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+// analyzePackage runs taint analysis on all functions of a package
+func analyzePackage(pkg *ssa.Package, set func(c *TaintConfig)) *TaintConfig {
+	funcs := ssautil.AllFunctions(pkg.Prog)
+	ruler := NewConfigRuler(testPack(), testPkg)
+	passThroughContainer := make(map[string]*PassThroughCache)
+	initMap := make(map[string]*ssa.Function)
+	c := &TaintConfig{PassThroughContainer: &passThroughContainer,
+		InitMap:            &initMap,
+		InterfaceHierarchy: NewInterfaceHierarchy(&funcs),
+		TaintGraph:         NewTaintGraph(&funcs, ruler),
+		Ruler:              ruler}
+	if set != nil {
+		set(c)
+	}
+
+	inits := make([]*ssa.Function, 0)
+	others := make([]*ssa.Function, 0)
+	for f := range funcs {
+		if f.Name() == "init" {
+			inits = append(inits, f)
+		} else {
+			others = append(others, f)
+		}
+	}
+	scheduler := NewScheduler(cha.CallGraph(pkg.Prog), c)
+	scheduler.Schedule(inits)
+	scheduler.Schedule(others)
+	return c
+}
+
+// findings returns findings of an analysis like Src#1->Cmd#0, sorted, names in the test package are short
+func findings(c *TaintConfig) []string {
+	res := make([]string, 0)
+	for _, finding := range FindPaths(c.TaintGraph, 10) {
+		res = append(res, shortName(finding.Source)+"#"+strconv.Itoa(finding.SourceIndex)+"->"+
+			shortName(finding.Sink)+"#"+strconv.Itoa(finding.SinkIndex))
+	}
+	sort.Strings(res)
+	return res
+}
+
+// shortName drops the path of the test package from a canonical name
+func shortName(canonical string) string {
+	return strings.ReplaceAll(canonical, testPkg+".", "")
+}
+
+// assertFindings checks findings of an analysis
+func assertFindings(t *testing.T, c *TaintConfig, want ...string) {
+	t.Helper()
+	want = append([]string{}, want...)
+	sort.Strings(want)
+	if got := findings(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+}
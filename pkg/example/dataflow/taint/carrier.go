@@ -0,0 +1,233 @@
+package taint
+
+import (
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+	"golang.org/x/tools/go/ssa"
+)
+
+// CarrierSeparator separates the kind and the name of a carrier
+// a carrier holds taint out of functions and passes it between them, e.g. chan:string is all channels of string
+// taint received from a carrier is named by the carrier, and the carrier is a node with index 0 in taint graph
+const CarrierSeparator = ":"
+
+// ChanCarrier returns the carrier of channels with the same element type as a channel
+func ChanCarrier(typ types.Type) string {
+	return "chan" + CarrierSeparator + typ.Underlying().(*types.Chan).Elem().String()
+}
+
+// IsCarrier checks whether a taint originates from a carrier
+func IsCarrier(taint string) bool {
+	return strings.Contains(TaintRoot(taint), CarrierSeparator)
+}
+
+// carrierNode returns the node of a carrier, and creates it if not exists
+func carrierNode(taintGraph *TaintGraph, carrier string) *Node {
+	key := carrier + "#" + strconv.Itoa(0)
+	if node, ok := (*taintGraph.Nodes)[key]; ok {
+		return node
+	}
+	node := &Node{Canonical: carrier, Index: 0, Out: make([]*Edge, 0), In: make([]*Edge, 0), IsIntra: true}
+	(*taintGraph.Nodes)[key] = node
+	return node
+}
+
+// taintOrigin returns the node a taint originates from and its key, which is a parameter or a free variable of the analyzed function, or a carrier
+func (s *TaintSwitcher) taintOrigin(taint string) (*Node, string, bool) {
+	taintGraph := s.taintAnalysis.config.TaintGraph
+	root := TaintRoot(taint)
+	if IsCarrier(root) {
+		return carrierNode(taintGraph, root), root + "#" + strconv.Itoa(0), true
+	}
+	for k, v := range s.taintAnalysis.Graph.Func.Params {
+		if v.Name() == root {
+			key := s.taintAnalysis.Graph.Func.String() + "#" + strconv.Itoa(k)
+			node, ok := (*taintGraph.Nodes)[key]
+			return node, key, ok
+		}
+	}
+	for k, v := range s.taintAnalysis.Graph.Func.FreeVars {
+		if v.Name() == root {
+			key := freeVarKey(s.taintAnalysis.Graph.Func, k)
+			node, ok := (*taintGraph.Nodes)[key]
+			return node, key, ok
+		}
+	}
+	return nil, "", false
+}
+
+// sendTaint passes taint of a value sent to a channel to the channel's carrier
+// the carrier only holds edges in taint graph, it is received as a taint named by the carrier
+func (s *TaintSwitcher) sendTaint(ch ssa.Value, x ssa.Value, inst ssa.Instruction) {
+	s.carry(ChanCarrier(ch.Type()), s.carriedTaint(x.Name()), inst)
+}
+
+// receiveTaint marks a value received from a channel with the channel's carrier
+func (s *TaintSwitcher) receiveTaint(name string, ch ssa.Value) {
+	SetTaint(s.outMap, name, ChanCarrier(ch.Type()))
+}
+
+// carry passes taint carried by a wrapper to a carrier
+// it is recorded in passthrough for callers, and creates edges from its origins to the carrier
+func (s *TaintSwitcher) carry(carrier string, carried *TaintWrapper, inst ssa.Instruction) {
+	s.taintAnalysis.passThrough.Send(carrier).inheritWrapper(carried, nil)
+	if !s.taintAnalysis.config.PassThroughOnly {
+		s.collectCarrierEdges(carrier, carried, inst)
+	}
+}
+
+// passSends passes taint of args to carriers by passthrough of a callee, args are values passed to the callee's parameters
+// edges are only created for a callee out of the module, because a callee in the module has edges to carriers from its own parameters
+func (s *TaintSwitcher) passSends(f *ssa.Function, c *PassThroughCache, args []ssa.Value, inst ssa.CallInstruction) {
+	taintGraph := s.taintAnalysis.config.TaintGraph
+	for carrier, indices := range c.Sends {
+		for _, j := range indices {
+			if j >= len(args) {
+				continue
+			}
+			carried := NewTaintWrapper()
+			carried.inheritWrapper(s.carriedTaint(args[j].Name()), nil, c.Removed[SendKey(carrier, j)]...)
+			s.taintAnalysis.passThrough.Send(carrier).inheritWrapper(carried, nil)
+			if node, ok := (*taintGraph.Nodes)[f.String()+"#"+strconv.Itoa(j)]; ok && node.IsIntra {
+				continue
+			}
+			if !s.taintAnalysis.config.PassThroughOnly {
+				s.collectCarrierEdges(carrier, carried, inst)
+			}
+		}
+	}
+}
+
+// collectCarrierEdges collects edges from origins of taint carried by a wrapper to a carrier
+func (s *TaintSwitcher) collectCarrierEdges(carrier string, carried *TaintWrapper, inst ssa.Instruction) {
+	taintGraph := s.taintAnalysis.config.TaintGraph
+	node2 := carrierNode(taintGraph, carrier)
+	key2 := carrier + "#" + strconv.Itoa(0)
+	for name := range *carried.innerTaint {
+		if node, key, ok := s.taintOrigin(name); ok && node.IsIntra && key != key2 {
+			edge := Edge{From: node.Canonical, FromIndex: node.Index, To: carrier, ToIndex: 0, Labels: s.edgeLabels(carried, name, node), Position: s.position(inst)}
+			if old, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
+				mergeEdge(old, &edge, node2)
+				continue
+			}
+			(*taintGraph.Edges)[key+"#"+key2] = &edge
+			node.Out = append(node.Out, &edge)
+			node2.In = append(node2.In, &edge)
+			passProperty(node2, &edge)
+		}
+	}
+}
+
+// Send returns the wrapper of taint passed to a carrier
+func (p *PassThrough) Send(carrier string) *TaintWrapper {
+	if _, ok := p.Sends[carrier]; !ok {
+		p.Sends[carrier] = NewTaintWrapper()
+	}
+	return p.Sends[carrier]
+}
+
+// SendKey returns the key of labels removed on the way from the j'th name to a carrier
+func SendKey(carrier string, j int) string {
+	return "Sends." + carrier + "." + strconv.Itoa(j)
+}
+
+// toSends records indices of names flowing to carriers
+func (p *PassThrough) toSends(c *PassThroughCache) {
+	for carrier, w := range p.Sends {
+		indices := make([]int, 0)
+		for j, name := range p.Names {
+			removed := make([]string, 0)
+			found := false
+			for taint := range *w.innerTaint {
+				if TaintRoot(taint) != name {
+					continue
+				}
+				// a way carries labels from any of its flows
+				if found {
+					removed = rule.Intersect(removed, w.Removed(taint))
+				} else {
+					removed = w.Removed(taint)
+				}
+				found = true
+			}
+			if found {
+				indices = append(indices, j)
+				c.setRemoved(SendKey(carrier, j), removed)
+			}
+		}
+		if len(indices) == 0 {
+			continue
+		}
+		if c.Sends == nil {
+			c.Sends = make(map[string][]int)
+		}
+		c.Sends[carrier] = indices
+	}
+}
+
+// CarrierKey returns the key of labels removed on the way from a carrier to the i'th value at a position
+func CarrierKey(position string, i int, carrier string) string {
+	return positionKey(position, i) + "." + carrier
+}
+
+// toCarriers records carriers flowing to the i'th value at a position, from the value or any of its fields
+func (p *PassThrough) toCarriers(c *PassThroughCache, position string, i int, w *TaintWrapper, fields map[string]*TaintWrapper) {
+	removed := make(map[string][]string)
+	collect := func(w *TaintWrapper) {
+		for taint := range *w.innerTaint {
+			if !IsCarrier(taint) {
+				continue
+			}
+			carrier := TaintRoot(taint)
+			// a way carries labels from any of its flows
+			if old, ok := removed[carrier]; ok {
+				removed[carrier] = rule.Intersect(old, w.Removed(taint))
+			} else {
+				removed[carrier] = w.Removed(taint)
+			}
+		}
+	}
+	collect(w)
+	for _, field := range fields {
+		collect(field)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	carriers := make([]string, 0)
+	for carrier, labels := range removed {
+		carriers = append(carriers, carrier)
+		c.setRemoved(CarrierKey(position, i, carrier), labels)
+	}
+	sort.Strings(carriers)
+	if c.Carriers == nil {
+		c.Carriers = make(map[string][]string)
+	}
+	c.Carriers[positionKey(position, i)] = carriers
+}
+
+// sanitizeCarriers removes labels from carriers flowing to the i'th value at a position, carriers are cleared if labels is empty
+func (c *PassThroughCache) sanitizeCarriers(labels []string, position string, i int) {
+	key := positionKey(position, i)
+	for _, carrier := range c.Carriers[key] {
+		if len(labels) == 0 {
+			delete(c.Removed, CarrierKey(position, i, carrier))
+		} else {
+			c.setRemoved(CarrierKey(position, i, carrier), rule.Union(c.Removed[CarrierKey(position, i, carrier)], labels))
+		}
+	}
+	if len(labels) == 0 {
+		delete(c.Carriers, key)
+	}
+}
+
+// inheritCarriers adds carriers flowing to the i'th value at a position of passthrough to a new taint at a call
+func inheritCarriers(newTaint *TaintWrapper, c *PassThroughCache, position string, i int) {
+	for _, carrier := range c.Carriers[positionKey(position, i)] {
+		newTaint.inheritWrapper(NewTaintWrapper(carrier), nil, c.Removed[CarrierKey(position, i, carrier)]...)
+	}
+}
@@ -16,10 +16,12 @@ type Imethod struct {
 }
 
 // InterfaceHierarchy represents implemetation relations
+// runtimeTypes is nil if implementations are not filtered by RTA
 type InterfaceHierarchy struct {
 	funcsBySig    *typeutil.Map
 	methodsMemo   *map[Imethod][]*ssa.Function
 	methodsByName *map[string][]*ssa.Function
+	runtimeTypes  *typeutil.Map
 }
 
 // LookupMethods returns an interface method's implemetations
@@ -29,7 +31,7 @@ func (i *InterfaceHierarchy) LookupMethods(I *types.Interface, m *types.Func) []
 	if !ok {
 		for _, f := range (*i.methodsByName)[m.Name()] {
 			C := f.Signature.Recv().Type() // named or *named
-			if types.Implements(C, I) {
+			if types.Implements(C, I) && i.isRuntimeType(C) {
 				methods = append(methods, f)
 			}
 		}
@@ -85,3 +87,55 @@ func NewInterfaceHierarchy(allFuncs *map[*ssa.Function]bool) *InterfaceHierarchy
 	}
 	return &InterfaceHierarchy{funcsBySig: &funcsBySig, methodsMemo: &methodsMemo, methodsByName: &methodsByName}
 }
+
+// FilterByRTA filters implementations like rapid type analysis, it should be called before any lookup
+// a method is an implementation only if its receiver type, or a pointer to it, is converted to an interface somewhere,
+// and a function is looked up by signature only if its address is taken
+func (i *InterfaceHierarchy) FilterByRTA(allFuncs *map[*ssa.Function]bool) {
+	var runtimeTypes typeutil.Map // value is bool
+	addressTaken := make(map[*ssa.Function]bool)
+	for f := range *allFuncs {
+		for _, b := range f.Blocks {
+			for _, instr := range b.Instrs {
+				if v, ok := instr.(*ssa.MakeInterface); ok {
+					runtimeTypes.Set(v.X.Type(), true)
+				}
+				var callee ssa.Value
+				if call, ok := instr.(ssa.CallInstruction); ok {
+					callee = call.Common().Value
+				}
+				for _, op := range instr.Operands(nil) {
+					// a function called directly is not address-taken
+					if g, ok := (*op).(*ssa.Function); ok && *op != callee {
+						addressTaken[g] = true
+					}
+				}
+			}
+		}
+	}
+	var funcsBySig typeutil.Map
+	i.funcsBySig.Iterate(func(signature types.Type, funcs any) {
+		taken := make([]*ssa.Function, 0)
+		for _, f := range funcs.([]*ssa.Function) {
+			if addressTaken[f] {
+				taken = append(taken, f)
+			}
+		}
+		funcsBySig.Set(signature, taken)
+	})
+	i.funcsBySig = &funcsBySig
+	i.runtimeTypes = &runtimeTypes
+	*i.methodsMemo = make(map[Imethod][]*ssa.Function)
+}
+
+// isRuntimeType checks whether values of a receiver type may be in an interface, it is always true if not filtered by RTA
+func (i *InterfaceHierarchy) isRuntimeType(C types.Type) bool {
+	if i.runtimeTypes == nil || i.runtimeTypes.At(C) != nil {
+		return true
+	}
+	// methods of T are also methods of *T
+	if _, ok := C.(*types.Pointer); !ok {
+		return i.runtimeTypes.At(types.NewPointer(C)) != nil
+	}
+	return false
+}
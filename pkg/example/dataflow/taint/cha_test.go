@@ -0,0 +1,59 @@
+package taint
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const joinSrc = `package test
+
+type Runner interface {
+	Run(s string) string
+}
+
+type Shell struct{}
+
+func (Shell) Run(s string) string { return s }
+
+type Safe struct{}
+
+func (Safe) Run(s string) string { return Clean(s) }
+
+type Quiet struct{}
+
+func (Quiet) Run(s string) string { return "" }
+
+func Clean(s string) string { return s }
+
+func Cmd(s string) {}
+
+func Do(r Runner, s string) string { return r.Run(s) }
+
+func upper(s string) string { return s }
+
+func lower(s string) string { return "" }
+
+func SrcJoin(a string) {
+	var r Runner = Safe{}
+	if len(a) > 3 {
+		r = Quiet{}
+	}
+	Cmd(Do(r, a))
+}
+
+func Call(h func(string) string, s string) string { return h(s) }
+
+func SrcFunc(a string) {
+	Cmd(Call(lower, a))
+}
+`
+
+func TestJoinPassThrough(t *testing.T) {
+	// Shell and upper are joined with the others, and Safe sanitizing doesn't hide the flow through Shell
+	assertFindings(t, analyze(t, joinSrc, nil), "SrcFunc#0->Cmd#0", "SrcJoin#0->Cmd#0")
+	// Shell is never converted to Runner and the address of upper is never taken
+	pkg := build(t, joinSrc)
+	funcs := ssautil.AllFunctions(pkg.Prog)
+	assertFindings(t, analyzePackage(pkg, func(c *TaintConfig) { c.InterfaceHierarchy.FilterByRTA(&funcs) }))
+}
@@ -0,0 +1,103 @@
+package taint
+
+import (
+	"strconv"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// freeVarNames returns names of free variables of a closure
+func freeVarNames(f *ssa.Function) []string {
+	names := make([]string, 0)
+	for _, freeVar := range f.FreeVars {
+		names = append(names, freeVar.Name())
+	}
+	return names
+}
+
+// freeVarIndex returns the index of the k'th free variable of a closure in passthrough and taint graph, which follows its parameters
+func freeVarIndex(f *ssa.Function, k int) int {
+	return len(f.Params) + k
+}
+
+// closureOf returns the closure of a function called by a call if it is known, or nil
+// the closure may be called directly, or stored to a local variable before
+func closureOf(f *ssa.Function, inst ssa.CallInstruction) *ssa.MakeClosure {
+	switch v := inst.Common().Value.(type) {
+	case *ssa.MakeClosure:
+		if v.Fn == f {
+			return v
+		}
+	case *ssa.UnOp:
+		if alloc, ok := v.X.(*ssa.Alloc); ok {
+			for _, ref := range *alloc.Referrers() {
+				if store, ok := ref.(*ssa.Store); ok {
+					if closure, ok := store.Val.(*ssa.MakeClosure); ok && closure.Fn == f {
+						return closure
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// callArgs returns values passed to a callee's parameters at a call, followed by bindings of free variables if the callee is a known closure
+func (s *TaintSwitcher) callArgs(f *ssa.Function, inst ssa.CallInstruction) []ssa.Value {
+	closure := closureOf(f, inst)
+	if closure == nil {
+		return inst.Common().Args
+	}
+	args := make([]ssa.Value, 0, len(inst.Common().Args)+len(closure.Bindings))
+	args = append(args, inst.Common().Args...)
+	return append(args, closure.Bindings...)
+}
+
+// passFreeVarTaint writes taint of free variables of a closure back to its bindings
+// args are values passed to the closure's parameters followed by its bindings, values not in args are skipped
+// it is only called for a known closure, a function called as a value has no bindings in args
+func (s *TaintSwitcher) passFreeVarTaint(passThroughCache *PassThroughCache, args []ssa.Value) {
+	n := len(args) - len(passThroughCache.FreeVars)
+	if n < 0 {
+		// bindings of a closure called as a value are unknown
+		return
+	}
+	for i, freeVar := range passThroughCache.FreeVars {
+		newTaint := NewTaintWrapper()
+		newFields := make(map[string]*TaintWrapper)
+		for _, p := range freeVar {
+			if p < len(args) && args[p] != nil {
+				s.inheritPassThrough(newTaint, newFields, passThroughCache, FreeVarPosition, i, p, args[p].Name())
+			}
+		}
+		inheritCarriers(newTaint, passThroughCache, FreeVarPosition, i)
+		// a captured variable is a pointer, so update further by the pointer
+		SetTaintWrapper(s.outMap, args[n+i].Name(), newTaint)
+		s.setFields(args[n+i].Name(), newFields)
+		s.passPointTaint(args[n+i])
+	}
+}
+
+// bindClosure passes taint of bindings into a closure when it is created
+// edges are collected from origins of bindings' taint to free variables of the closure,
+// and taint written between free variables is written back, because the closure may be called anywhere
+func (s *TaintSwitcher) bindClosure(inst *ssa.MakeClosure) {
+	f, ok := inst.Fn.(*ssa.Function)
+	if !ok {
+		return
+	}
+	// parameters are unknown until the closure is called
+	args := make([]ssa.Value, len(f.Params), len(f.Params)+len(inst.Bindings))
+	args = append(args, inst.Bindings...)
+	if !s.taintAnalysis.config.PassThroughOnly {
+		s.collectArgEdges(f, args, inst)
+	}
+	if passThroughCache, ok := (*s.taintAnalysis.config.PassThroughContainer)[f.String()]; ok {
+		s.passFreeVarTaint(passThroughCache, args)
+	}
+}
+
+// freeVarKey returns the key of the k'th free variable of a closure in taint graph
+func freeVarKey(f *ssa.Function, k int) string {
+	return f.String() + "#" + strconv.Itoa(freeVarIndex(f, k))
+}
@@ -0,0 +1,21 @@
+package taint
+
+import "testing"
+
+const closureSrc = `package test
+
+func Cmd(s string) {}
+
+func SrcHandler(a, b string, call func(string, string)) {
+	x := ""
+	f := func(p, q string) { x = p + q }
+	f(a, b)
+	call(a, b)
+	Cmd(x)
+}
+`
+
+func TestClosures(t *testing.T) {
+	// a function value with the signature of a closure does not write to free variables of the closure
+	assertFindings(t, analyze(t, closureSrc, nil), "SrcHandler#0->Cmd#0", "SrcHandler#1->Cmd#0")
+}
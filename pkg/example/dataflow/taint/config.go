@@ -1,8 +1,6 @@
 package taint
 
 import (
-	"container/list"
-
 	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/ssa"
@@ -12,8 +10,6 @@ import (
 type TaintConfig struct {
 	PassThroughContainer *map[string]*PassThroughCache
 	InitMap              *map[string]*ssa.Function
-	History              *map[string]bool
-	CallStack            *list.List
 	InterfaceHierarchy   *InterfaceHierarchy
 	TaintGraph           *TaintGraph
 	UsePointerAnalysis   bool
@@ -21,8 +17,13 @@ type TaintConfig struct {
 	Ruler                rule.Ruler
 	PassThroughOnly      bool
 	TargetFunc           string
+	TraceDstDir          string
+	GraphDstDir          string
 	Debug                bool
 	PassBack             bool
+	AccessPathDepth      int
+	ContextSensitive     bool
+	ImplicitFlow         bool
 }
 
 // Gostd reprents all go standard library's PkgPath
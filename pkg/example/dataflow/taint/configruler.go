@@ -0,0 +1,143 @@
+package taint
+
+import (
+	"go/types"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+)
+
+// ConfigRuler is a rule.Ruler which decides sources, sinks and sanitizers by a rule.Pack
+type ConfigRuler struct {
+	rule.BaseRuler
+	Pack       *rule.Pack
+	moduleName []string
+}
+
+// NewConfigRuler returns a ConfigRuler
+func NewConfigRuler(pack *rule.Pack, moduleName ...string) *ConfigRuler {
+	configRuler := new(ConfigRuler)
+	configRuler.Pack = pack
+	configRuler.moduleName = moduleName
+	return configRuler
+}
+
+// LoadConfigRuler returns a ConfigRuler with rules loaded from files
+func LoadConfigRuler(paths []string, moduleName ...string) (*ConfigRuler, error) {
+	pack, err := rule.Load(paths...)
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigRuler(pack, moduleName...), nil
+}
+
+// IsIntra returns whether a node is from target module
+func (r *ConfigRuler) IsIntra(_f any) bool {
+	switch node := (_f).(type) {
+	case *Node:
+		return isIntra(node, r.moduleName)
+	}
+	return false
+}
+
+// IsSource returns whether a node is a source
+func (r *ConfigRuler) IsSource(_f any) bool {
+	return r.Source(_f) != nil
+}
+
+// IsSink returns whether a node is a sink
+func (r *ConfigRuler) IsSink(_f any) bool {
+	return r.Sink(_f) != nil
+}
+
+// IsSanitizer returns whether a node is a sanitizer
+func (r *ConfigRuler) IsSanitizer(_f any) bool {
+	return r.Sanitizer(_f) != nil
+}
+
+// Source returns the source rule matching a node, or nil
+// results of a function are asked by rule.ResultIndex, and a source rule only taints them with an index of -1
+func (r *ConfigRuler) Source(_f any) *rule.Rule {
+	node, ok := _f.(*Node)
+	if !ok || node.Index != rule.ResultIndex {
+		return find(r.Pack.Sources, _f)
+	}
+	rules := make([]*rule.Rule, 0)
+	for _, source := range r.Pack.Sources {
+		if source.Index != nil {
+			rules = append(rules, source)
+		}
+	}
+	return find(rules, node)
+}
+
+// Sink returns the sink rule matching a node, or nil
+func (r *ConfigRuler) Sink(_f any) *rule.Rule {
+	return find(r.Pack.Sinks, _f)
+}
+
+// Sanitizer returns the sanitizer rule matching a node, or nil
+// a sanitizer rule without index only sanitizes results of a function, which are asked by rule.ResultIndex
+func (r *ConfigRuler) Sanitizer(_f any) *rule.Rule {
+	node, ok := _f.(*Node)
+	if !ok || node.Index == rule.ResultIndex {
+		return find(r.Pack.Sanitizers, _f)
+	}
+	rules := make([]*rule.Rule, 0)
+	for _, sanitizer := range r.Pack.Sanitizers {
+		if sanitizer.Index != nil {
+			rules = append(rules, sanitizer)
+		}
+	}
+	return find(rules, node)
+}
+
+// Labels returns default labels and categories of all rules
+func (r *ConfigRuler) Labels() []string {
+	labels := rule.Labels
+	for _, rules := range [][]*rule.Rule{r.Pack.Sources, r.Pack.Sinks, r.Pack.Sanitizers} {
+		for _, x := range rules {
+			if x.Category != "" {
+				labels = rule.Union(labels, []string{x.Category})
+			}
+		}
+	}
+	return labels
+}
+
+// SourceLabels returns the category of the source rule matching a node
+func (r *ConfigRuler) SourceLabels(_f any) []string {
+	return category(r.Source(_f))
+}
+
+// SinkLabels returns the category of the sink rule matching a node
+func (r *ConfigRuler) SinkLabels(_f any) []string {
+	return category(r.Sink(_f))
+}
+
+// SanitizerLabels returns the category of the sanitizer rule matching a node
+func (r *ConfigRuler) SanitizerLabels(_f any) []string {
+	return category(r.Sanitizer(_f))
+}
+
+// category returns the category of a rule as labels, a rule without category has no labels
+func category(x *rule.Rule) []string {
+	if x == nil || x.Category == "" {
+		return nil
+	}
+	return []string{x.Category}
+}
+
+// find returns the first rule matching a node or a canonical name
+func find(rules []*rule.Rule, _f any) *rule.Rule {
+	switch node := _f.(type) {
+	case *Node:
+		var signature *types.Signature
+		if node.Function != nil {
+			signature = node.Function.Signature
+		}
+		return rule.Find(rules, node.Canonical, signature, node.Index)
+	case string:
+		return rule.Find(rules, node, nil, 0)
+	}
+	return nil
+}
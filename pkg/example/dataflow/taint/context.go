@@ -0,0 +1,171 @@
+package taint
+
+import (
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// ContextSeparator separates a function and its context in a key of passthrough
+// e.g. pkg.Apply@0=*pkg.Escaper is the passthrough of pkg.Apply whose first parameter is a *pkg.Escaper
+const ContextSeparator = "@"
+
+// Context represents a calling context of a function
+// it binds parameters of interface types to concrete types and parameters of function types to functions passed at a call site
+type Context struct {
+	Types map[int]types.Type
+	Funcs map[int]*ssa.Function
+}
+
+// NewContext returns a Context
+func NewContext() *Context {
+	context := new(Context)
+	context.Types = make(map[int]types.Type)
+	context.Funcs = make(map[int]*ssa.Function)
+	return context
+}
+
+// IsEmpty checks whether the context binds nothing
+func (c *Context) IsEmpty() bool {
+	return len(c.Types) == 0 && len(c.Funcs) == 0
+}
+
+// String returns bindings of the context sorted by parameter indices, like 0=*pkg.Escaper,2=pkg.Clean
+func (c *Context) String() string {
+	bindings := make(map[int]string)
+	for i, typ := range c.Types {
+		bindings[i] = typ.String()
+	}
+	for i, f := range c.Funcs {
+		bindings[i] = f.String()
+	}
+	indices := make([]int, 0)
+	for i := range bindings {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	s := make([]string, 0)
+	for _, i := range indices {
+		s = append(s, strconv.Itoa(i)+"="+bindings[i])
+	}
+	return strings.Join(s, ",")
+}
+
+// ContextKey returns the key of passthrough of a function in a context
+func ContextKey(f *ssa.Function, context *Context) string {
+	if context == nil {
+		return f.String()
+	}
+	return f.String() + ContextSeparator + context.String()
+}
+
+// RunContext runs a taint analysis on a function in a context, and returns its passthrough in the context
+// a function which is being analyzed in the same context, like a recursive one, gets null passthrough
+func RunContext(f *ssa.Function, context *Context, c *TaintConfig) (*PassThroughCache, bool) {
+	key := ContextKey(f, context)
+	if passThroughCache, ok := (*c.PassThroughContainer)[key]; ok {
+		return passThroughCache, true
+	}
+	if needNull(f, c) {
+		return nil, false
+	}
+	names := make([]string, 0)
+	for _, param := range f.Params {
+		names = append(names, param.Name())
+	}
+	passThrough := NewPassThrough(names, f.Signature.Recv() != nil, f.Signature.Results().Len(), f.Signature.Params().Len())
+	passThrough.BindFreeVars(freeVarNames(f))
+	(*c.PassThroughContainer)[key] = passThrough.ToCache()
+	doRun(f, context, c)
+	return (*c.PassThroughContainer)[key], true
+}
+
+// callContext returns the context of a callee at a call, args are values passed to the callee's parameters
+// a parameter of the caller passes its binding on, so a context reaches callees of callees
+// it returns nil if nothing is bound
+func (s *TaintSwitcher) callContext(f *ssa.Function, args []ssa.Value) *Context {
+	context := NewContext()
+	for i, arg := range args {
+		if i >= len(f.Params) {
+			break
+		}
+		if types.IsInterface(f.Params[i].Type()) {
+			if v, ok := arg.(*ssa.MakeInterface); ok {
+				context.Types[i] = v.X.Type()
+			} else if typ := s.boundType(arg); typ != nil {
+				context.Types[i] = typ
+			}
+		} else if _, ok := f.Params[i].Type().Underlying().(*types.Signature); ok {
+			if v, ok := arg.(*ssa.Function); ok {
+				context.Funcs[i] = v
+			} else if fn := s.boundFunc(arg); fn != nil {
+				context.Funcs[i] = fn
+			}
+		}
+	}
+	if context.IsEmpty() {
+		return nil
+	}
+	return context
+}
+
+// boundIndex returns the index of a parameter in the analyzed function if it is bound in the context, else -1
+func (s *TaintSwitcher) boundIndex(v ssa.Value) int {
+	if s.taintAnalysis.context == nil {
+		return -1
+	}
+	for i, param := range s.taintAnalysis.Graph.Func.Params {
+		if param == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// boundType returns the concrete type bound to a parameter by the context, or nil
+func (s *TaintSwitcher) boundType(v ssa.Value) types.Type {
+	if i := s.boundIndex(v); i >= 0 {
+		return s.taintAnalysis.context.Types[i]
+	}
+	return nil
+}
+
+// boundFunc returns the function bound to a parameter by the context, or nil
+func (s *TaintSwitcher) boundFunc(v ssa.Value) *ssa.Function {
+	if i := s.boundIndex(v); i >= 0 {
+		return s.taintAnalysis.context.Funcs[i]
+	}
+	return nil
+}
+
+// boundMethod returns the method of the concrete type bound to a parameter by the context, or nil
+func (s *TaintSwitcher) boundMethod(v ssa.Value, m *types.Func) *ssa.Function {
+	typ := s.boundType(v)
+	if typ == nil {
+		return nil
+	}
+	prog := s.taintAnalysis.Graph.Func.Prog
+	selection := prog.MethodSets.MethodSet(typ).Lookup(m.Pkg(), m.Name())
+	if selection == nil {
+		return nil
+	}
+	return prog.MethodValue(selection)
+}
+
+// lookupPassThrough returns passthrough of a callee at a call, args are values passed to the callee's parameters
+// when analysis is context sensitive, the callee is analyzed in the context of the call if anything is bound
+func (s *TaintSwitcher) lookupPassThrough(f *ssa.Function, args []ssa.Value) (*PassThroughCache, bool) {
+	c := s.taintAnalysis.config
+	if c.ContextSensitive {
+		if context := s.callContext(f, args); context != nil {
+			if passThroughCache, ok := RunContext(f, context, c); ok {
+				return passThroughCache, true
+			}
+		}
+	}
+	passThroughCache, ok := (*c.PassThroughContainer)[f.String()]
+	return passThroughCache, ok
+}
@@ -0,0 +1,37 @@
+package taint
+
+import "testing"
+
+const contextSrc = `package test
+
+type Cleaner interface {
+	Clean(s string, n int) string
+}
+
+type Keep struct{}
+
+type Drop struct{}
+
+func (Drop) Clean(s string, n int) string { return "" }
+
+func (Keep) Clean(s string, n int) string {
+	if n > 5 {
+		return Apply(Keep{}, s, n-1)
+	}
+	return s
+}
+
+func Apply(e Cleaner, s string, n int) string { return e.Clean(s, n) }
+
+func Cmd(s string) {}
+
+func SrcKeep(a string) { Cmd(Apply(Keep{}, a, 10)) }
+
+func SrcDrop(a string) { Cmd(Apply(Drop{}, a, 10)) }
+`
+
+func TestContext(t *testing.T) {
+	assertFindings(t, analyze(t, contextSrc, nil), "SrcDrop#0->Cmd#0", "SrcKeep#0->Cmd#0")
+	// Apply in the context of Keep is in a recursive SCC with Keep.Clean
+	assertFindings(t, analyze(t, contextSrc, func(c *TaintConfig) { c.ContextSensitive = true }), "SrcKeep#0->Cmd#0")
+}
@@ -0,0 +1,155 @@
+package taint
+
+import (
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// elementField returns the field of an element of a slice, an array or a map in access paths, like [0] or ["k"]
+// an element is only a field at a constant index or key, else it returns ""
+// a key with a separator of access paths can't be a field either
+func elementField(container ssa.Value, index ssa.Value) string {
+	if !hasElements(container.Type()) {
+		return ""
+	}
+	c, ok := index.(*ssa.Const)
+	if !ok || c.Value == nil {
+		return ""
+	}
+	switch c.Value.Kind() {
+	case constant.Int:
+		if i, ok := constant.Int64Val(c.Value); ok {
+			return "[" + strconv.FormatInt(i, 10) + "]"
+		}
+	case constant.String:
+		key := strconv.Quote(constant.StringVal(c.Value))
+		if !strings.Contains(key, ".") && !strings.Contains(key, FieldSeparator) {
+			return "[" + key + "]"
+		}
+	}
+	return ""
+}
+
+// hasElements checks whether a type is a slice, an array, a pointer to array or a map
+func hasElements(typ types.Type) bool {
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	switch typ.Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		return true
+	}
+	return false
+}
+
+// isElement checks whether a field of an access path is an element
+func isElement(field string) bool {
+	return strings.HasPrefix(field, "[")
+}
+
+// elementIndex returns the index of an element field of a slice or an array, or false for a map key
+func elementIndex(field string) (int, bool) {
+	i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(field, "["), "]"))
+	return i, err == nil
+}
+
+// constLen returns the length of a slice if it is known, like a slice literal or nil
+func constLen(v ssa.Value) (int, bool) {
+	switch v := v.(type) {
+	case *ssa.Const:
+		if v.IsNil() {
+			return 0, true
+		}
+	case *ssa.Slice:
+		if v.Low != nil || v.High != nil {
+			return 0, false
+		}
+		if pointer, ok := v.X.Type().Underlying().(*types.Pointer); ok {
+			if array, ok := pointer.Elem().Underlying().(*types.Array); ok {
+				return int(array.Len()), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// constIndex returns the value of a constant index, nil means 0
+func constIndex(v ssa.Value) (int, bool) {
+	if v == nil {
+		return 0, true
+	}
+	if c, ok := v.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.Int {
+		if i, ok := constant.Int64Val(c.Value); ok {
+			return int(i), true
+		}
+	}
+	return 0, false
+}
+
+// readElement passes taint of an element of a container at an index to dst
+// taint of the container as a whole and its fields out of elements are shared by all elements,
+// besides them, dst gets taint of the element at a constant index, else taint of all elements
+func (s *TaintSwitcher) readElement(dst string, container ssa.Value, index ssa.Value) {
+	src := container.Name()
+	if s.depth() == 0 {
+		PassTaint(s.outMap, dst, src)
+		return
+	}
+	field := elementField(container, index)
+	if field == "" {
+		s.shiftElements(dst, src, 0, false)
+		return
+	}
+	s.readPath(GetTaintWrapper(s.outMap, dst), src, field)
+	for _, sub := range subPaths(s.outMap, src, "") {
+		path := sub
+		if first, rest, _ := strings.Cut(sub, "."); isElement(first) {
+			if first != field {
+				continue
+			}
+			path = rest
+		}
+		GetTaintWrapper(s.outMap, fieldKey(dst, path)).InheritTaint(s.outMap, fieldKey(src, sub))
+	}
+}
+
+// writeElement passes taint of src to an element of dst at an index
+// taint written at an unknown index is taint of dst as a whole, which is shared by all elements
+func (s *TaintSwitcher) writeElement(dst ssa.Value, index ssa.Value, src string) {
+	if field := elementField(dst, index); field != "" {
+		s.writeField(dst.Name(), field, src)
+		return
+	}
+	s.passValue(dst.Name(), src)
+}
+
+// shiftElements passes taint of a slice or an array src to dst, and moves elements by offset, like a slice or an append
+// elements moved before 0 are dropped, and elements are merged into dst as a whole if offset is unknown
+func (s *TaintSwitcher) shiftElements(dst string, src string, offset int, known bool) {
+	if s.depth() == 0 {
+		PassTaint(s.outMap, dst, src)
+		return
+	}
+	GetTaintWrapper(s.outMap, dst).InheritTaint(s.outMap, src)
+	for _, sub := range subPaths(s.outMap, src, "") {
+		path := sub
+		if first, rest, _ := strings.Cut(sub, "."); isElement(first) {
+			i, ok := elementIndex(first)
+			switch {
+			case !known:
+				path = rest
+			case !ok:
+				// a key of a map is not moved
+			case i+offset < 0:
+				continue
+			default:
+				path = joinPath("["+strconv.Itoa(i+offset)+"]", rest)
+			}
+		}
+		GetTaintWrapper(s.outMap, fieldKey(dst, path)).InheritTaint(s.outMap, fieldKey(src, sub))
+	}
+}
@@ -0,0 +1,64 @@
+package taint
+
+import "testing"
+
+const elementSrc = `package test
+
+func Cmd(name string, args ...string) {}
+
+func SrcArgs0(ref string) {
+	args := []string{"git", "log", ref}
+	Cmd(args[0])
+}
+
+func SrcArgs2(ref string) {
+	args := []string{"git", "log", ref}
+	Cmd(args[2])
+}
+
+func SrcArgsRest(ref string) {
+	args := []string{"git", "log", ref}
+	Cmd(args[0], args[1:]...)
+}
+
+func SrcMapSafe(ref string) {
+	m := map[string]string{}
+	m["safe"] = "x"
+	m["bad"] = ref
+	Cmd(m["safe"])
+}
+
+func SrcMapBad(ref string) {
+	m := map[string]string{}
+	m["safe"] = "x"
+	m["bad"] = ref
+	Cmd(m["bad"])
+}
+
+func SrcMapKey(ref string, key string) {
+	m := map[string]string{}
+	m[key] = ref
+	Cmd(m["safe"])
+}
+
+func SrcAppend(ref string) {
+	more := append([]string{"a", "b"}, ref)
+	Cmd(more[1])
+}
+
+func SrcIndex(ref string, i int) {
+	a := make([]string, 3)
+	a[i] = ref
+	Cmd(a[0])
+}
+`
+
+func TestElements(t *testing.T) {
+	// a container is a whole value without access paths
+	assertFindings(t, analyze(t, elementSrc, nil),
+		"SrcAppend#0->Cmd#0", "SrcArgs0#0->Cmd#0", "SrcArgs2#0->Cmd#0", "SrcArgsRest#0->Cmd#0", "SrcArgsRest#0->Cmd#1",
+		"SrcIndex#0->Cmd#0", "SrcMapBad#0->Cmd#0", "SrcMapKey#0->Cmd#0", "SrcMapKey#1->Cmd#0", "SrcMapSafe#0->Cmd#0")
+	// elements at constant indices and keys are told apart, and taint written at an unknown index reaches all of them
+	assertFindings(t, analyze(t, elementSrc, func(c *TaintConfig) { c.AccessPathDepth = 2 }),
+		"SrcArgs2#0->Cmd#0", "SrcArgsRest#0->Cmd#1", "SrcIndex#0->Cmd#0", "SrcMapBad#0->Cmd#0", "SrcMapKey#0->Cmd#0", "SrcMapKey#1->Cmd#0")
+}
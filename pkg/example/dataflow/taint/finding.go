@@ -0,0 +1,131 @@
+package taint
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+)
+
+// Finding represents a path from a source to a sink in a taint graph
+type Finding struct {
+	Source      string
+	SourceIndex int
+	Sink        string
+	SinkIndex   int
+	Labels      []string
+	Path        []*Edge
+}
+
+// step represents a node reached by a path when finding paths
+type step struct {
+	node   *Node
+	labels []string
+	path   []*Edge
+}
+
+// FindPaths finds shortest paths from sources to sinks in a taint graph
+// a path has at most depth edges, depth <= 0 means no limit
+// a path only carries labels on all of its edges, and reaches a sink only if the sink fires on one of them
+// there is one finding for a source and a sink, which is a shortest path between them
+// a path only carrying the implicit label reaches any sink, and is a finding only if there is no explicit one
+func FindPaths(taintGraph *TaintGraph, depth int) []*Finding {
+	keys := make([]string, 0)
+	for key, node := range *taintGraph.Nodes {
+		if node.IsSource && node.IsIntra && len(node.Out) != 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	findings := make([]*Finding, 0)
+	for _, key := range keys {
+		findings = append(findings, findPathsFrom(taintGraph, (*taintGraph.Nodes)[key], depth)...)
+	}
+	return findings
+}
+
+// findPathsFrom finds shortest paths from a source to sinks by BFS
+func findPathsFrom(taintGraph *TaintGraph, source *Node, depth int) []*Finding {
+	findings := make([]*Finding, 0)
+	found := make(map[string]bool)
+	implicits := make([]*Finding, 0)
+	// a source passes implicit taint too, it only goes on edges of implicit flows
+	start := rule.Union(source.Labels, []string{rule.Implicit})
+	// labels which have reached a node, a node is visited again only with new labels
+	visited := make(map[*Node][]string)
+	visited[source] = start
+	queue := []*step{{node: source, labels: start, path: make([]*Edge, 0)}}
+	for len(queue) != 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if depth > 0 && len(cur.path) >= depth {
+			continue
+		}
+		for _, edge := range sortedEdges(cur.node.Out) {
+			labels := rule.Intersect(cur.labels, edge.Labels)
+			if len(labels) == 0 {
+				continue
+			}
+			next := edgeTarget(taintGraph, edge)
+			if next == nil {
+				continue
+			}
+			path := make([]*Edge, len(cur.path), len(cur.path)+1)
+			copy(path, cur.path)
+			path = append(path, edge)
+			if edge.ToIsSink && next.IsSink {
+				key := edge.To + "#" + strconv.Itoa(edge.ToIndex)
+				sinkLabels := rule.Intersect(labels, next.Labels)
+				if !found[key] && len(sinkLabels) != 0 {
+					found[key] = true
+					findings = append(findings, &Finding{Source: source.Canonical, SourceIndex: source.Index,
+						Sink: edge.To, SinkIndex: edge.ToIndex, Labels: sinkLabels, Path: path})
+				} else if !found[key+"#"+rule.Implicit] && len(labels) == 1 && labels[0] == rule.Implicit {
+					found[key+"#"+rule.Implicit] = true
+					implicits = append(implicits, &Finding{Source: source.Canonical, SourceIndex: source.Index,
+						Sink: edge.To, SinkIndex: edge.ToIndex, Labels: labels, Path: path})
+				}
+			}
+			old := visited[next]
+			if len(rule.Union(old, labels)) == len(old) {
+				continue
+			}
+			visited[next] = rule.Union(old, labels)
+			queue = append(queue, &step{node: next, labels: labels, path: path})
+		}
+	}
+	for _, finding := range implicits {
+		if !found[finding.Sink+"#"+strconv.Itoa(finding.SinkIndex)] {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// edgeTarget returns the node an edge goes to
+// nodes of methods and signatures are only recorded by canonical names
+func edgeTarget(taintGraph *TaintGraph, edge *Edge) *Node {
+	if node, ok := (*taintGraph.Nodes)[edge.To+"#"+strconv.Itoa(edge.ToIndex)]; ok {
+		return node
+	}
+	return (*taintGraph.Nodes)[edge.To]
+}
+
+// sortedEdges returns distinct edges sorted by their ends, so findings are stable
+func sortedEdges(edges []*Edge) []*Edge {
+	seen := make(map[*Edge]bool)
+	sorted := make([]*Edge, 0, len(edges))
+	for _, edge := range edges {
+		if !seen[edge] {
+			seen[edge] = true
+			sorted = append(sorted, edge)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].To != sorted[j].To {
+			return sorted[i].To < sorted[j].To
+		}
+		return sorted[i].ToIndex < sorted[j].ToIndex
+	})
+	return sorted
+}
@@ -0,0 +1,79 @@
+package taint
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// GlobalCarrier returns the carrier of a package-level variable, like global:net/http.DefaultClient
+func GlobalCarrier(g *ssa.Global) string {
+	return "global" + CarrierSeparator + g.String()
+}
+
+// globalOf returns the global variable a value addresses or points into, or nil
+// e.g. &G.Host, &G[0] and *G of a pointer G are all in G
+func globalOf(v ssa.Value) *ssa.Global {
+	for {
+		switch x := v.(type) {
+		case *ssa.Global:
+			return x
+		case *ssa.FieldAddr:
+			v = x.X
+		case *ssa.IndexAddr:
+			v = x.X
+		case *ssa.UnOp:
+			if x.Op != token.MUL {
+				return nil
+			}
+			v = x.X
+		default:
+			return nil
+		}
+	}
+}
+
+// globalNode returns the node of a global variable's carrier, and records the declaration of the variable
+func (s *TaintSwitcher) globalNode(g *ssa.Global) *Node {
+	node := carrierNode(s.taintAnalysis.config.TaintGraph, GlobalCarrier(g))
+	if !node.Position.IsValid() {
+		node.Position = s.taintAnalysis.Graph.Func.Prog.Fset.Position(g.Pos())
+	}
+	return node
+}
+
+// storeGlobal passes taint of a value stored to an address in a global variable to the variable's carrier
+func (s *TaintSwitcher) storeGlobal(addr ssa.Value, name string, inst ssa.Instruction) {
+	g := globalOf(addr)
+	if g == nil {
+		return
+	}
+	s.globalNode(g)
+	s.carry(GlobalCarrier(g), s.carriedTaint(name), inst)
+}
+
+// loadGlobal marks a value loaded from an address in a global variable with the variable's carrier
+// the package initializer initializes variables of its package in order, so it only sees taint stored before
+func (s *TaintSwitcher) loadGlobal(name string, addr ssa.Value) {
+	g := globalOf(addr)
+	if g == nil {
+		return
+	}
+	f := s.taintAnalysis.Graph.Func
+	if f.Synthetic == "package initializer" && g.Pkg == f.Pkg {
+		return
+	}
+	SetTaint(s.outMap, name, GlobalCarrier(g))
+}
+
+// storeGlobalArgs passes taint written back to args of a call to global variables they refer to
+// only pointers, maps and slices can be written back, other args are copies of global variables
+func (s *TaintSwitcher) storeGlobalArgs(inst ssa.CallInstruction) {
+	for _, arg := range inst.Common().Args {
+		switch arg.Type().Underlying().(type) {
+		case *types.Pointer, *types.Map, *types.Slice:
+			s.storeGlobal(arg, arg.Name(), inst)
+		}
+	}
+}
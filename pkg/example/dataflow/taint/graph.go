@@ -1,6 +1,7 @@
 package taint
 
 import (
+	"go/token"
 	"strconv"
 
 	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
@@ -25,12 +26,14 @@ func NewTaintGraph(allFuncs *map[*ssa.Function]bool, ruler rule.Ruler) *TaintGra
 			node := &Node{Function: f, Canonical: f.String(), Index: 0, Out: make([]*Edge, 0), In: make([]*Edge, 0)}
 			decidePropertry(node, ruler)
 			node.IsStatic = true
+			node.Position = f.Prog.Fset.Position(f.Pos())
 			(*callGraph.Nodes)[f.String()+"#"+strconv.Itoa(0)] = node
 			n := f.Signature.Params().Len()
 			for i := 0; i < n; i++ {
 				node := &Node{Function: f, Canonical: f.String(), Index: i + 1, Out: make([]*Edge, 0), In: make([]*Edge, 0)}
 				decidePropertry(node, ruler)
 				node.IsStatic = true
+				node.Position = f.Prog.Fset.Position(f.Pos())
 				(*callGraph.Nodes)[f.String()+"#"+strconv.Itoa(i+1)] = node
 			}
 		} else {
@@ -39,14 +42,24 @@ func NewTaintGraph(allFuncs *map[*ssa.Function]bool, ruler rule.Ruler) *TaintGra
 				node := &Node{Function: f, Canonical: f.String(), Index: i, Out: make([]*Edge, 0), In: make([]*Edge, 0)}
 				decidePropertry(node, ruler)
 				node.IsStatic = true
+				node.Position = f.Prog.Fset.Position(f.Pos())
 				(*callGraph.Nodes)[f.String()+"#"+strconv.Itoa(i)] = node
 			}
 		}
+		for k := range f.FreeVars {
+			// free variables of a closure follow its parameters
+			node := &Node{Function: f, Canonical: f.String(), Index: freeVarIndex(f, k), Out: make([]*Edge, 0), In: make([]*Edge, 0)}
+			decidePropertry(node, ruler)
+			node.IsStatic = true
+			node.Position = f.Prog.Fset.Position(f.Pos())
+			(*callGraph.Nodes)[freeVarKey(f, k)] = node
+		}
 	}
 	return callGraph
 }
 
 // Node represents a taint node
+// Position is the declaration of its function, and invalid for nodes only with type information
 type Node struct {
 	Function    *ssa.Function
 	IsSignature bool
@@ -57,11 +70,14 @@ type Node struct {
 	IsIntra     bool
 	Canonical   string
 	Index       int
+	Labels      []string
+	Position    token.Position
 	Out         []*Edge
 	In          []*Edge
 }
 
 // Edge represents a taint edge
+// Position is the call which creates it
 type Edge struct {
 	From          string
 	FromIndex     int
@@ -71,4 +87,6 @@ type Edge struct {
 	ToIsSink      bool
 	ToIsSignature bool
 	ToIsStatic    bool
+	Labels        []string
+	Position      token.Position
 }
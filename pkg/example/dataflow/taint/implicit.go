@@ -0,0 +1,158 @@
+package taint
+
+import (
+	"go/types"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+	"golang.org/x/tools/go/ssa"
+)
+
+// postDominators returns post-dominators of each block of a function, indexed by blocks' indices
+// blocks which never reach a return, like an infinite loop, are treated as exits
+func postDominators(f *ssa.Function) [][]bool {
+	n := len(f.Blocks)
+	exits := make([]bool, n)
+	queue := make([]*ssa.BasicBlock, 0)
+	for _, b := range f.Blocks {
+		if len(b.Succs) == 0 {
+			exits[b.Index] = true
+			queue = append(queue, b)
+		}
+	}
+	reach := make([]bool, n)
+	for len(queue) != 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if reach[b.Index] {
+			continue
+		}
+		reach[b.Index] = true
+		queue = append(queue, b.Preds...)
+	}
+	pdom := make([][]bool, n)
+	for _, b := range f.Blocks {
+		pdom[b.Index] = make([]bool, n)
+		if exits[b.Index] || !reach[b.Index] {
+			pdom[b.Index][b.Index] = true
+			continue
+		}
+		for i := range pdom[b.Index] {
+			pdom[b.Index][i] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, b := range f.Blocks {
+			if exits[b.Index] || !reach[b.Index] {
+				continue
+			}
+			for i := range pdom[b.Index] {
+				if !pdom[b.Index][i] || i == b.Index {
+					continue
+				}
+				// a block post-dominates b only if it post-dominates all successors of b
+				for _, succ := range b.Succs {
+					if !pdom[succ.Index][i] {
+						pdom[b.Index][i] = false
+						changed = true
+						break
+					}
+				}
+			}
+		}
+	}
+	return pdom
+}
+
+// controlDependence returns branches each block of a function is control dependent on, indexed by blocks' indices
+// a block depends on an *ssa.If if it post-dominates a successor of the If but doesn't strictly post-dominate the If,
+// so blocks after the branches join again are not affected by the If
+func controlDependence(f *ssa.Function) [][]*ssa.If {
+	pdom := postDominators(f)
+	deps := make([][]*ssa.If, len(f.Blocks))
+	for _, b := range f.Blocks {
+		branch, ok := b.Instrs[len(b.Instrs)-1].(*ssa.If)
+		if !ok {
+			continue
+		}
+		for _, d := range f.Blocks {
+			if d != b && pdom[b.Index][d.Index] {
+				continue
+			}
+			for _, succ := range b.Succs {
+				if pdom[succ.Index][d.Index] {
+					deps[d.Index] = append(deps[d.Index], branch)
+					break
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// branchesOf returns branches deciding a value defined by an instruction
+// besides branches its block depends on, a phi depends on branches its predecessors are in or depend on,
+// because they decide which edge is chosen
+func (s *TaintSwitcher) branchesOf(inst ssa.Instruction) []*ssa.If {
+	deps := s.taintAnalysis.controlDeps
+	branches := append([]*ssa.If{}, deps[inst.Block().Index]...)
+	if _, ok := inst.(*ssa.Phi); ok {
+		for _, pred := range inst.Block().Preds {
+			if branch, ok := pred.Instrs[len(pred.Instrs)-1].(*ssa.If); ok {
+				branches = append(branches, branch)
+			}
+			branches = append(branches, deps[pred.Index]...)
+		}
+	}
+	return branches
+}
+
+// implicitTaint returns taint implicitly passed by branches, which only carries the implicit label
+func (s *TaintSwitcher) implicitTaint(branches []*ssa.If) *TaintWrapper {
+	w := NewTaintWrapper()
+	explicit := rule.LabelsOf(s.taintAnalysis.config.Ruler)
+	for _, branch := range branches {
+		w.inheritWrapper(GetTaintWrapper(s.outMap, branch.Cond.Name()), nil, explicit...)
+	}
+	return w
+}
+
+// passImplicitTaint passes taint of conditions of branches to values defined under them, if ImplicitFlow is set
+// values are defined by instructions, by stores and by returns, and a tuple is passed by its extracts
+func (s *TaintSwitcher) passImplicitTaint(inst ssa.Instruction) {
+	if s.taintAnalysis.controlDeps == nil {
+		return
+	}
+	branches := s.branchesOf(inst)
+	if len(branches) == 0 {
+		return
+	}
+	implicit := s.implicitTaint(branches)
+	if len(*implicit.innerTaint) == 0 {
+		return
+	}
+	switch inst := inst.(type) {
+	case *ssa.Store:
+		s.addImplicitTaint(inst.Addr.Name(), implicit)
+		s.passPointTaint(inst.Addr)
+		s.storeGlobal(inst.Addr, inst.Addr.Name(), inst)
+	case *ssa.Return:
+		for _, w := range s.taintAnalysis.passThrough.Results {
+			w.inheritWrapper(implicit, nil)
+		}
+	case ssa.Value:
+		if _, ok := inst.Type().(*types.Tuple); ok {
+			return
+		}
+		s.addImplicitTaint(inst.Name(), implicit)
+	}
+}
+
+// addImplicitTaint adds implicit taint to the wrapper with a key
+// the old wrapper is not changed because it may be shared by other flows
+func (s *TaintSwitcher) addImplicitTaint(name string, implicit *TaintWrapper) {
+	newTaint := NewTaintWrapper()
+	newTaint.InheritTaint(s.outMap, name)
+	newTaint.inheritWrapper(implicit, nil)
+	SetTaintWrapper(s.outMap, name, newTaint)
+}
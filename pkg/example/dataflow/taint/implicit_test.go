@@ -0,0 +1,60 @@
+package taint
+
+import (
+	"testing"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+)
+
+const implicitSrc = `package test
+
+func Cmd(s string) {}
+
+func Clean(s string) string { return s }
+
+func choose(role string) string {
+	cmd := "ls"
+	if role == "admin" {
+		cmd = "rm"
+	}
+	return cmd
+}
+
+func after(role string) string {
+	if role == "admin" {
+		println("hi")
+	}
+	return "fixed"
+}
+
+func SrcPhi(a string) { Cmd(choose(a)) }
+
+func SrcAfter(a string) { Cmd(after(a)) }
+
+func SrcClean(a string) { Cmd(choose(Clean(a))) }
+
+func SrcBoth(a string) {
+	cmd := a
+	if a == "" {
+		cmd = "ls"
+	}
+	Cmd(cmd)
+}
+`
+
+func TestImplicitFlow(t *testing.T) {
+	assertFindings(t, analyze(t, implicitSrc, nil), "SrcBoth#0->Cmd#0")
+	// the phi after the branch on role depends on it, and a return after the branches join again doesn't
+	c := analyze(t, implicitSrc, func(c *TaintConfig) { c.ImplicitFlow = true })
+	assertFindings(t, c, "SrcBoth#0->Cmd#0", "SrcPhi#0->Cmd#0")
+	// an implicit finding is only reported if there is no explicit one
+	for _, finding := range FindPaths(c.TaintGraph, 10) {
+		want := rule.Implicit
+		if finding.Source == testPkg+".SrcBoth" {
+			want = rule.CmdI
+		}
+		if len(finding.Labels) != 1 || finding.Labels[0] != want {
+			t.Errorf("got labels %v of %s, want %s", finding.Labels, finding.Source, want)
+		}
+	}
+}
@@ -1,18 +1,63 @@
 package taint
 
+import (
+	"sort"
+	"strconv"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+)
+
 // PassThrough represents a passthrough
+// Fields records taint of fields of the receiver, results and parameters, keyed by position and access path
+// Sends records taint passed to carriers, keyed by carrier
+// FreeVars records taint of free variables of a closure, their names follow names of parameters in Names
 type PassThrough struct {
-	Names   []string
-	Recv    *TaintWrapper
-	Results []*TaintWrapper
-	Params  []*TaintWrapper
+	Names    []string
+	Recv     *TaintWrapper
+	Results  []*TaintWrapper
+	Params   []*TaintWrapper
+	FreeVars []*TaintWrapper
+	Fields   map[string]map[string]*TaintWrapper
+	Sends    map[string]*TaintWrapper
 }
 
 // PassThroughCache represents a passthrough cache
+// Removed records labels removed on the way from a parameter to the receiver, a result or a parameter,
+// keyed by RemovedKey
+// Fields records flows between access paths on the way, keyed by RemovedKey, a way without it is a flow between whole values
+// Carriers records carriers flowing to the receiver, a result or a parameter, keyed like Recv.0,
+// labels removed on the way from a carrier are in Removed, keyed by CarrierKey
+// Sends records indices of names flowing to a carrier, keyed by carrier, labels removed on the way are in Removed, keyed by SendKey
+// FreeVars records indices of names flowing to free variables of a closure, a free variable's index follows indices of parameters
 type PassThroughCache struct {
-	Recv    []int
-	Results [][]int
-	Params  [][]int
+	Recv     []int
+	Results  [][]int
+	Params   [][]int
+	FreeVars [][]int                 `json:",omitempty"`
+	Removed  map[string][]string     `json:",omitempty"`
+	Fields   map[string][]*FieldFlow `json:",omitempty"`
+	Carriers map[string][]string     `json:",omitempty"`
+	Sends    map[string][]int        `json:",omitempty"`
+}
+
+// FieldFlow represents a flow from an access path of a parameter to an access path of a value
+// an empty access path is the whole parameter or value
+type FieldFlow struct {
+	From string `json:",omitempty"`
+	To   string `json:",omitempty"`
+}
+
+// Positions of a passthrough used in RemovedKey
+const (
+	RecvPosition    = "Recv"
+	ResultPosition  = "Results"
+	ParamPosition   = "Params"
+	FreeVarPosition = "FreeVars"
+)
+
+// RemovedKey returns the key of labels removed on the way from the j'th name to the i'th value at a position
+func RemovedKey(position string, i int, j int) string {
+	return positionKey(position, i) + "." + strconv.Itoa(j)
 }
 
 // NewPassThrough return a PassThrough
@@ -21,6 +66,8 @@ func NewPassThrough(names []string, recv bool, result int, param int) *PassThrou
 	passThrough.Names = names
 	passThrough.Results = make([]*TaintWrapper, 0)
 	passThrough.Params = make([]*TaintWrapper, 0)
+	passThrough.Fields = make(map[string]map[string]*TaintWrapper)
+	passThrough.Sends = make(map[string]*TaintWrapper)
 	// init param taints in passThrough
 	if recv {
 		// if the function has a receiver, add a position for receiver's taint
@@ -39,43 +86,153 @@ func NewPassThrough(names []string, recv bool, result int, param int) *PassThrou
 	return passThrough
 }
 
+// BindFreeVars adds free variables of a closure to a passthrough, after its parameters
+func (p *PassThrough) BindFreeVars(names []string) {
+	p.Names = append(p.Names, names...)
+	for _, name := range names {
+		p.FreeVars = append(p.FreeVars, NewTaintWrapper(name))
+	}
+}
+
 // ToCache tranforms a passthrough to a passthrough cache
 func (p *PassThrough) ToCache() *PassThroughCache {
 	passThroughCache := NewPassThroughCache(false, 0, 0)
-	n := len(p.Names)
 	if p.HasRecv() {
-		singlePassThrough := make([]int, 0)
-		for i := 0; i < n; i++ {
-			// for reciver, checks its taints from which param, and records
-			if ok := p.Recv.HasTaint(p.Names[i]); ok {
-				singlePassThrough = append(singlePassThrough, i)
-			}
-		}
-		passThroughCache.Recv = singlePassThrough
+		// for reciver, checks its taints from which param, and records
+		passThroughCache.Recv = p.toCache(passThroughCache, RecvPosition, 0, p.Recv, 0)
+	}
+	recv := 0
+	if p.HasRecv() {
+		recv = 1
 	}
 	m := p.ResultNum()
 	for i := 0; i < m; i++ {
-		singlePassThrough := make([]int, 0)
-		for j := 0; j < n; j++ {
-			// for every return value, checks its taints from which param, and records
-			if ok := p.Results[i].HasTaint(p.Names[j]); ok {
-				singlePassThrough = append(singlePassThrough, j)
-			}
-		}
-		passThroughCache.Results = append(passThroughCache.Results, singlePassThrough)
+		// for every return value, checks its taints from which param, and records
+		passThroughCache.Results = append(passThroughCache.Results, p.toCache(passThroughCache, ResultPosition, i, p.Results[i], -1))
 	}
 	m = p.ParamNum()
 	for i := 0; i < m; i++ {
-		singlePassThrough := make([]int, 0)
-		for j := 0; j < n; j++ {
-			// for every parameter value, checks its taints from which param, and records
-			if ok := p.Params[i].HasTaint(p.Names[j]); ok {
-				singlePassThrough = append(singlePassThrough, j)
+		// for every parameter value, checks its taints from which param, and records
+		passThroughCache.Params = append(passThroughCache.Params, p.toCache(passThroughCache, ParamPosition, i, p.Params[i], recv+i))
+	}
+	for i, freeVar := range p.FreeVars {
+		// for every free variable, checks its taints from which param or free variable, and records
+		passThroughCache.FreeVars = append(passThroughCache.FreeVars, p.toCache(passThroughCache, FreeVarPosition, i, freeVar, recv+m+i))
+	}
+	p.toSends(passThroughCache)
+	return passThroughCache
+}
+
+// toCache returns indices of names flowing to the i'th value at a position, and records removed labels and field flows
+// self is the index of the value in names, a flow from a field of the value to the same field is skipped
+func (p *PassThrough) toCache(c *PassThroughCache, position string, i int, w *TaintWrapper, self int) []int {
+	fields := p.Fields[positionKey(position, i)]
+	paths := make([]string, 0)
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	p.toCarriers(c, position, i, w, fields)
+	singlePassThrough := make([]int, 0)
+	for j, name := range p.Names {
+		flows := make([]*FieldFlow, 0)
+		removed := make([]string, 0)
+		collect := func(w *TaintWrapper, to string) {
+			for taint := range *w.innerTaint {
+				from := TaintPath(taint)
+				if TaintRoot(taint) != name || (j == self && to != "" && from == to) {
+					continue
+				}
+				// a way carries labels from any of its flows
+				if len(flows) == 0 {
+					removed = w.Removed(taint)
+				} else {
+					removed = rule.Intersect(removed, w.Removed(taint))
+				}
+				flows = append(flows, &FieldFlow{From: from, To: to})
 			}
 		}
-		passThroughCache.Params = append(passThroughCache.Params, singlePassThrough)
+		collect(w, "")
+		for _, path := range paths {
+			collect(fields[path], path)
+		}
+		if len(flows) == 0 {
+			continue
+		}
+		singlePassThrough = append(singlePassThrough, j)
+		c.setRemoved(RemovedKey(position, i, j), removed)
+		c.setFields(RemovedKey(position, i, j), flows)
 	}
-	return passThroughCache
+	return singlePassThrough
+}
+
+// setFields sets field flows of a way with a key after pruning and sorting them
+// nothing is recorded for a flow between whole values
+func (c *PassThroughCache) setFields(key string, flows []*FieldFlow) {
+	flows = pruneFlows(flows)
+	if len(flows) == 1 && flows[0].From == "" && flows[0].To == "" {
+		return
+	}
+	sort.Slice(flows, func(x, y int) bool {
+		if flows[x].To != flows[y].To {
+			return flows[x].To < flows[y].To
+		}
+		return flows[x].From < flows[y].From
+	})
+	if c.Fields == nil {
+		c.Fields = make(map[string][]*FieldFlow)
+	}
+	c.Fields[key] = flows
+}
+
+// pruneFlows removes field flows implied by others
+// a flow from a.x to b.x is implied by a flow from a to b, because fields of a way's source flow to the same fields
+func pruneFlows(flows []*FieldFlow) []*FieldFlow {
+	seen := make(map[FieldFlow]bool)
+	for _, flow := range flows {
+		seen[*flow] = true
+	}
+	pruned := make([]*FieldFlow, 0, len(flows))
+	for _, flow := range flows {
+		if !impliedFlow(flow, seen) {
+			pruned = append(pruned, flow)
+		}
+	}
+	return pruned
+}
+
+// impliedFlow checks whether a flow is implied by a flow in seen after removing their common last fields
+func impliedFlow(flow *FieldFlow, seen map[FieldFlow]bool) bool {
+	from, to := flow.From, flow.To
+	for from != "" && to != "" {
+		fromParent, fromField := splitPath(from)
+		toParent, toField := splitPath(to)
+		if fromField != toField {
+			return false
+		}
+		from, to = fromParent, toParent
+		if seen[FieldFlow{From: from, To: to}] {
+			return true
+		}
+	}
+	return false
+}
+
+// Field returns the wrapper of an access path of the i'th value at a position
+func (p *PassThrough) Field(position string, i int, path string) *TaintWrapper {
+	key := positionKey(position, i)
+	if _, ok := p.Fields[key]; !ok {
+		p.Fields[key] = make(map[string]*TaintWrapper)
+	}
+	if _, ok := p.Fields[key][path]; !ok {
+		p.Fields[key][path] = NewTaintWrapper()
+	}
+	return p.Fields[key][path]
+}
+
+// positionKey returns the key of the i'th value at a position
+func positionKey(position string, i int) string {
+	return position + "." + strconv.Itoa(i)
 }
 
 // RecvName returns the receiver's name
@@ -109,6 +266,11 @@ func (p *PassThrough) ParamNum() int {
 	return len(p.Params)
 }
 
+// FreeVarName returns the i'th free variable's name
+func (p *PassThrough) FreeVarName(i int) string {
+	return p.Names[len(p.Names)-len(p.FreeVars)+i]
+}
+
 // NewPassThroughCache returns a PassThroughCache
 func NewPassThroughCache(recv bool, result int, param int) *PassThroughCache {
 	passThroughCache := new(PassThroughCache)
@@ -140,3 +302,185 @@ func (c *PassThroughCache) ResultNum() int {
 func (c *PassThroughCache) ParamNum() int {
 	return len(c.Params)
 }
+
+// Sanitize sanitizes passthrough of results and parameters which are sanitized by a ruler
+// passthrough is cleared if the sanitizer removes all labels, else labels are removed from it
+// node represents the function of the cache, its index is changed when asking the ruler
+func (c *PassThroughCache) Sanitize(node *Node, ruler rule.Ruler) {
+	node.Index = rule.ResultIndex
+	if ruler.IsSanitizer(node) {
+		labels := rule.SanitizerLabelsOf(ruler, node)
+		for i := range c.Results {
+			c.Results[i] = c.sanitize(c.Results[i], labels, ResultPosition, i)
+		}
+	}
+	recv := 0
+	if c.HasRecv() {
+		recv = 1
+		node.Index = 0
+		if ruler.IsSanitizer(node) {
+			c.Recv = c.sanitize(c.Recv, rule.SanitizerLabelsOf(ruler, node), RecvPosition, 0)
+		}
+	}
+	for i := range c.Params {
+		node.Index = recv + i
+		if ruler.IsSanitizer(node) {
+			c.Params[i] = c.sanitize(c.Params[i], rule.SanitizerLabelsOf(ruler, node), ParamPosition, i)
+		}
+	}
+}
+
+// sanitize clears passthrough of the i'th value at a position, or removes labels from it
+func (c *PassThroughCache) sanitize(passThrough []int, labels []string, position string, i int) []int {
+	c.sanitizeCarriers(labels, position, i)
+	if len(labels) == 0 {
+		for _, j := range passThrough {
+			delete(c.Removed, RemovedKey(position, i, j))
+			delete(c.Fields, RemovedKey(position, i, j))
+		}
+		return make([]int, 0)
+	}
+	for _, j := range passThrough {
+		key := RemovedKey(position, i, j)
+		c.setRemoved(key, rule.Union(c.Removed[key], labels))
+	}
+	return passThrough
+}
+
+// RemovedLabels returns labels removed on the way from the j'th name to the i'th value at a position
+func (c *PassThroughCache) RemovedLabels(position string, i int, j int) []string {
+	return c.Removed[RemovedKey(position, i, j)]
+}
+
+// setRemoved sets labels removed with a key, nothing is recorded if no label is removed
+func (c *PassThroughCache) setRemoved(key string, labels []string) {
+	if len(labels) == 0 {
+		return
+	}
+	if c.Removed == nil {
+		c.Removed = make(map[string][]string)
+	}
+	c.Removed[key] = labels
+}
+
+// SanitizePassThrough sanitizes every passthrough cache in a container by a ruler
+func SanitizePassThrough(passThroughContainer *map[string]*PassThroughCache, ruler rule.Ruler) {
+	for canonical, passThroughCache := range *passThroughContainer {
+		passThroughCache.Sanitize(&Node{Canonical: canonical}, ruler)
+	}
+}
+
+// JoinPassThrough returns the join of passthrough caches of callees with the same signature, like implementations of an interface method
+// a way in any of them is in the join, and carries labels from any of them
+func JoinPassThrough(caches []*PassThroughCache) *PassThroughCache {
+	if len(caches) == 1 {
+		return caches[0]
+	}
+	join := NewPassThroughCache(false, 0, 0)
+	removed := make(map[string][]string)
+	fields := make(map[string]map[FieldFlow]bool)
+	joinWay := func(c *PassThroughCache, key string) {
+		if old, ok := removed[key]; ok {
+			removed[key] = rule.Intersect(old, c.Removed[key])
+		} else {
+			removed[key] = c.Removed[key]
+		}
+	}
+	joinPosition := func(position string, i int, indices func(c *PassThroughCache) []int) []int {
+		key := positionKey(position, i)
+		seen := make(map[int]bool)
+		carriers := make(map[string]bool)
+		for _, c := range caches {
+			for _, j := range indices(c) {
+				seen[j] = true
+				joinWay(c, RemovedKey(position, i, j))
+				// a way without field flows is a flow between whole values
+				flows, ok := c.Fields[RemovedKey(position, i, j)]
+				if !ok {
+					flows = []*FieldFlow{{}}
+				}
+				if _, ok := fields[RemovedKey(position, i, j)]; !ok {
+					fields[RemovedKey(position, i, j)] = make(map[FieldFlow]bool)
+				}
+				for _, flow := range flows {
+					fields[RemovedKey(position, i, j)][*flow] = true
+				}
+			}
+			for _, carrier := range c.Carriers[key] {
+				carriers[carrier] = true
+				joinWay(c, CarrierKey(position, i, carrier))
+			}
+		}
+		if len(carriers) != 0 {
+			if join.Carriers == nil {
+				join.Carriers = make(map[string][]string)
+			}
+			for carrier := range carriers {
+				join.Carriers[key] = append(join.Carriers[key], carrier)
+			}
+			sort.Strings(join.Carriers[key])
+		}
+		return sortedIndices(seen)
+	}
+	if caches[0].HasRecv() {
+		join.Recv = joinPosition(RecvPosition, 0, func(c *PassThroughCache) []int {
+			return c.Recv
+		})
+	}
+	for i := range caches[0].Results {
+		join.Results = append(join.Results, joinPosition(ResultPosition, i, func(c *PassThroughCache) []int {
+			if i < len(c.Results) {
+				return c.Results[i]
+			}
+			return nil
+		}))
+	}
+	for i := range caches[0].Params {
+		join.Params = append(join.Params, joinPosition(ParamPosition, i, func(c *PassThroughCache) []int {
+			if i < len(c.Params) {
+				return c.Params[i]
+			}
+			return nil
+		}))
+	}
+	sends := make(map[string]map[int]bool)
+	for _, c := range caches {
+		for carrier, indices := range c.Sends {
+			if _, ok := sends[carrier]; !ok {
+				sends[carrier] = make(map[int]bool)
+			}
+			for _, j := range indices {
+				sends[carrier][j] = true
+				joinWay(c, SendKey(carrier, j))
+			}
+		}
+	}
+	for carrier, seen := range sends {
+		if join.Sends == nil {
+			join.Sends = make(map[string][]int)
+		}
+		join.Sends[carrier] = sortedIndices(seen)
+	}
+	for key, labels := range removed {
+		join.setRemoved(key, labels)
+	}
+	for key, seen := range fields {
+		flows := make([]*FieldFlow, 0)
+		for flow := range seen {
+			flow := flow
+			flows = append(flows, &flow)
+		}
+		join.setFields(key, flows)
+	}
+	return join
+}
+
+// sortedIndices returns sorted indices in a set
+func sortedIndices(seen map[int]bool) []int {
+	indices := make([]int, 0)
+	for j := range seen {
+		indices = append(indices, j)
+	}
+	sort.Ints(indices)
+	return indices
+}
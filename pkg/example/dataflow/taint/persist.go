@@ -18,13 +18,13 @@ func PersistPassThrough(passThroughContainer *map[string]*PassThroughCache, dst
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 	res, err := json.Marshal(*passThroughContainer)
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(f, string(res))
-	f.Close()
-	return nil
+	_, err = fmt.Fprint(f, string(res))
+	return err
 }
 
 // PersistTaintGraph stores taint edges to target destination
@@ -33,13 +33,28 @@ func PersistTaintGraph(edges *map[string]*Edge, dst string) error {
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 	res, err := json.Marshal(*edges)
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(f, string(res))
-	f.Close()
-	return nil
+	_, err = fmt.Fprint(f, string(res))
+	return err
+}
+
+// PersistFindings stores findings to target destination
+func PersistFindings(findings []*Finding, dst string) error {
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := json.Marshal(findings)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, string(res))
+	return err
 }
 
 // PersistToNeo4j stores taint edges to neo4j database
@@ -58,16 +73,19 @@ func PersistToNeo4j(nodes *map[string]*Node, edges *map[string]*Edge, uri string
 		_, err = session.WriteTransaction(func(transaction neo4j.Transaction) (any, error) {
 			if node.IsSource && node.IsIntra && len(node.Out) != 0 {
 				_, _ = transaction.Run(
-					"CREATE (node:Source) SET node={id:$Id, name:$Canonical, index:$Index}",
-					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index})
+					"CREATE (node:Source) SET node={id:$Id, name:$Canonical, index:$Index, labels:$Labels, file:$File, line:$Line, column:$Column}",
+					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index, "Labels": node.Labels,
+						"File": node.Position.Filename, "Line": node.Position.Line, "Column": node.Position.Column})
 			} else if node.IsSink && len(node.In) != 0 {
 				_, _ = transaction.Run(
-					"CREATE (node:Sink) SET node={id:$Id, name:$Canonical, index:$Index}",
-					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index})
+					"CREATE (node:Sink) SET node={id:$Id, name:$Canonical, index:$Index, labels:$Labels, file:$File, line:$Line, column:$Column}",
+					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index, "Labels": node.Labels,
+						"File": node.Position.Filename, "Line": node.Position.Line, "Column": node.Position.Column})
 			} else if node.IsIntra && len(node.In)+len(node.Out) != 0 {
 				_, _ = transaction.Run(
-					"CREATE (node:Intra) SET node={id:$Id, name:$Canonical, index:$Index}",
-					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index})
+					"CREATE (node:Intra) SET node={id:$Id, name:$Canonical, index:$Index, labels:$Labels, file:$File, line:$Line, column:$Column}",
+					map[string]any{"Id": id, "Canonical": node.Canonical, "Index": node.Index, "Labels": node.Labels,
+						"File": node.Position.Filename, "Line": node.Position.Line, "Column": node.Position.Column})
 			}
 			return nil, nil
 		})
@@ -80,8 +98,9 @@ func PersistToNeo4j(nodes *map[string]*Node, edges *map[string]*Edge, uri string
 		id2 := strconv.FormatUint(maphash.String(seed, edge.To+strconv.Itoa(edge.ToIndex)), 10)
 		_, err = session.WriteTransaction(func(transaction neo4j.Transaction) (any, error) {
 			_, _ = transaction.Run(
-				"MATCH (from),(to) WHERE from.id=$Id1 and to.id=$Id2 CREATE (from)-[r:CALL]->(to)",
-				map[string]any{"Id1": id1, "Id2": id2})
+				"MATCH (from),(to) WHERE from.id=$Id1 and to.id=$Id2 CREATE (from)-[r:CALL {labels:$Labels, file:$File, line:$Line, column:$Column}]->(to)",
+				map[string]any{"Id1": id1, "Id2": id2, "Labels": edge.Labels,
+					"File": edge.Position.Filename, "Line": edge.Position.Line, "Column": edge.Position.Column})
 			return nil, nil
 		})
 		if err != nil {
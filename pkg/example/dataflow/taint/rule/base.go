@@ -32,3 +32,8 @@ func (r *BaseRuler) IsSink(_f any) bool {
 func (r *BaseRuler) IsIntra(_f any) bool {
 	return false
 }
+
+// IsSanitizer returns whether a node is a sanitizer
+func (r *BaseRuler) IsSanitizer(_f any) bool {
+	return false
+}
@@ -1,8 +1,23 @@
 package rule
 
+// ResultIndex is the index of a node which represents results of a function
+// it is used to ask whether results of a function are sanitized
+const ResultIndex = -1
+
 // Ruler defines whether a node is interesting in taint analysis
 type Ruler interface {
 	IsSink(any) bool
 	IsSource(any) bool
 	IsIntra(any) bool
+	IsSanitizer(any) bool
+}
+
+// LabelRuler is an optional interface of a Ruler, which decides vulnerability labels of taint
+// a source emits labels and a sink fires on labels from SourceLabels and SinkLabels, all labels if they are empty,
+// a sanitizer removes labels from SanitizerLabels, all taint if it is empty
+type LabelRuler interface {
+	Labels() []string
+	SourceLabels(any) []string
+	SinkLabels(any) []string
+	SanitizerLabels(any) []string
 }
@@ -0,0 +1,84 @@
+package rule
+
+import "sort"
+
+// Vulnerability labels carried by taint
+const (
+	CmdI          = "cmdi"
+	PathTraversal = "traversal"
+	SQLi          = "sqli"
+	SSRF          = "ssrf"
+	XSS           = "xss"
+)
+
+// Implicit is the label of taint implicitly passed by branches on tainted conditions
+// it is not a default label, and only carried when implicit flows are tracked
+const Implicit = "implicit"
+
+// Labels are all default labels, sorted like Union and Intersect
+var Labels = []string{CmdI, SQLi, SSRF, PathTraversal, XSS}
+
+// LabelsOf returns all labels a ruler knows, default labels if it is not a LabelRuler
+func LabelsOf(ruler Ruler) []string {
+	if labelRuler, ok := ruler.(LabelRuler); ok {
+		return labelRuler.Labels()
+	}
+	return Labels
+}
+
+// SourceLabelsOf returns labels emitted by a source, all labels if the ruler doesn't decide
+func SourceLabelsOf(ruler Ruler, node any) []string {
+	if labelRuler, ok := ruler.(LabelRuler); ok {
+		if labels := labelRuler.SourceLabels(node); len(labels) != 0 {
+			return labels
+		}
+	}
+	return LabelsOf(ruler)
+}
+
+// SinkLabelsOf returns labels a sink fires on, all labels if the ruler doesn't decide
+func SinkLabelsOf(ruler Ruler, node any) []string {
+	if labelRuler, ok := ruler.(LabelRuler); ok {
+		if labels := labelRuler.SinkLabels(node); len(labels) != 0 {
+			return labels
+		}
+	}
+	return LabelsOf(ruler)
+}
+
+// SanitizerLabelsOf returns labels removed by a sanitizer, empty means all labels
+func SanitizerLabelsOf(ruler Ruler, node any) []string {
+	if labelRuler, ok := ruler.(LabelRuler); ok {
+		return labelRuler.SanitizerLabels(node)
+	}
+	return nil
+}
+
+// Intersect returns sorted labels in both x and y
+func Intersect(x []string, y []string) []string {
+	labels := make([]string, 0)
+	for _, a := range x {
+		for _, b := range y {
+			if a == b {
+				labels = append(labels, a)
+				break
+			}
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Union returns sorted labels in x or y
+func Union(x []string, y []string) []string {
+	set := make(map[string]bool)
+	for _, label := range append(append([]string{}, x...), y...) {
+		set[label] = true
+	}
+	labels := make([]string, 0, len(set))
+	for label := range set {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
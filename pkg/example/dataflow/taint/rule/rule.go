@@ -0,0 +1,214 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule represents a declarative rule which matches a function, or an argument of it if Index is set
+// all conditions set in a rule must be satisfied
+type Rule struct {
+	Function string   `json:"function,omitempty" yaml:"function,omitempty"`
+	Regex    string   `json:"regex,omitempty" yaml:"regex,omitempty"`
+	Package  string   `json:"package,omitempty" yaml:"package,omitempty"`
+	Receiver string   `json:"receiver,omitempty" yaml:"receiver,omitempty"`
+	Params   []string `json:"params,omitempty" yaml:"params,omitempty"`
+	Fields   []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Index    *int     `json:"index,omitempty" yaml:"index,omitempty"`
+	Category string   `json:"category,omitempty" yaml:"category,omitempty"`
+	regex    *regexp.Regexp
+	err      error
+}
+
+// Pack represents a pack of rules, several packs can be merged into one
+type Pack struct {
+	Sources    []*Rule `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Sinks      []*Rule `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+	Sanitizers []*Rule `json:"sanitizers,omitempty" yaml:"sanitizers,omitempty"`
+}
+
+// NewPack returns an empty Pack
+func NewPack() *Pack {
+	pack := new(Pack)
+	pack.Sources = make([]*Rule, 0)
+	pack.Sinks = make([]*Rule, 0)
+	pack.Sanitizers = make([]*Rule, 0)
+	return pack
+}
+
+// Load loads rule files and merges them into a Pack
+// a file ends with .yaml or .yml is decoded as yaml, others are decoded as json
+func Load(paths ...string) (*Pack, error) {
+	pack := NewPack()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p := new(Pack)
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(content, p)
+		default:
+			err = json.Unmarshal(content, p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if err := p.Compile(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		pack.Merge(p)
+	}
+	return pack, nil
+}
+
+// Merge appends rules of another Pack
+func (p *Pack) Merge(other *Pack) {
+	p.Sources = append(p.Sources, other.Sources...)
+	p.Sinks = append(p.Sinks, other.Sinks...)
+	p.Sanitizers = append(p.Sanitizers, other.Sanitizers...)
+}
+
+// Compile compiles regular expressions of rules and returns the first invalid one
+// a Pack built in code should be compiled before analysis, otherwise a rule with an invalid regex never matches
+func (p *Pack) Compile() error {
+	for _, rules := range [][]*Rule{p.Sources, p.Sinks, p.Sanitizers} {
+		for _, r := range rules {
+			if err := r.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compile compiles the regular expression of a rule once
+func (r *Rule) compile() error {
+	if r.Regex == "" || r.regex != nil || r.err != nil {
+		return r.err
+	}
+	regex, err := regexp.Compile(r.Regex)
+	if err != nil {
+		r.err = fmt.Errorf("regex %q: %w", r.Regex, err)
+		return r.err
+	}
+	r.regex = regex
+	return nil
+}
+
+// Find returns the first rule matching a function at an index, or nil if no rule matches
+// canonical is the name of a function like (*database/sql.DB).Query,
+// signature is the signature of the function and can be nil if it is unknown,
+// index is the index of a parameter, where the receiver of a method is 0
+func Find(rules []*Rule, canonical string, signature *types.Signature, index int) *Rule {
+	for _, r := range rules {
+		if r.Match(canonical, signature, index) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Match returns whether a rule matches a function at an index
+func (r *Rule) Match(canonical string, signature *types.Signature, index int) bool {
+	if r.Index != nil && *r.Index != index {
+		return false
+	}
+	if r.Function != "" && r.Function != canonical {
+		return false
+	}
+	if r.Regex != "" {
+		if r.regex == nil && r.err == nil {
+			if err := r.compile(); err != nil {
+				log.Println(err)
+			}
+		}
+		if r.err != nil || !r.regex.MatchString(canonical) {
+			return false
+		}
+	}
+	pkg, recv := split(canonical)
+	if r.Package != "" && r.Package != pkg {
+		return false
+	}
+	if r.Receiver != "" && r.Receiver != recv {
+		return false
+	}
+	if len(r.Params) != 0 {
+		if signature == nil {
+			return false
+		}
+		for _, param := range r.Params {
+			if !hasParam(signature, param) {
+				return false
+			}
+		}
+	}
+	if len(r.Fields) != 0 && (signature == nil || !hasField(signature, r.Fields)) {
+		return false
+	}
+	return true
+}
+
+// split returns the package path and the receiver type of a canonical name
+// e.g. (*database/sql.DB).Query has package database/sql and receiver *database/sql.DB
+func split(canonical string) (string, string) {
+	name := canonical
+	recv := ""
+	if strings.HasPrefix(name, "(") {
+		if i := strings.Index(name, ")"); i != -1 {
+			recv = name[1:i]
+			name = strings.TrimPrefix(recv, "*")
+		}
+	}
+	if i := strings.Index(name, "["); i != -1 {
+		// drop type arguments of a generic function
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "."); i != -1 {
+		return name[:i], recv
+	}
+	return "", recv
+}
+
+// hasParam returns whether a signature has a parameter of a type
+func hasParam(signature *types.Signature, param string) bool {
+	for i := 0; i < signature.Params().Len(); i++ {
+		if signature.Params().At(i).Type().String() == param {
+			return true
+		}
+	}
+	return false
+}
+
+// hasField returns whether the receiver of a signature is a struct, or a pointer to it, with a field of one of types
+func hasField(signature *types.Signature, fields []string) bool {
+	if signature.Recv() == nil {
+		return false
+	}
+	recv := signature.Recv().Type()
+	if pointer, ok := recv.(*types.Pointer); ok {
+		recv = pointer.Elem()
+	}
+	typ, ok := recv.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < typ.NumFields(); i++ {
+		for _, field := range fields {
+			if typ.Field(i).Type().String() == field {
+				return true
+			}
+		}
+	}
+	return false
+}
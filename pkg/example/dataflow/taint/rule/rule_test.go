@@ -0,0 +1,63 @@
+package rule
+
+import (
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// controller returns the signature of a method whose receiver is a pointer to a struct embedding web.Controller
+func controller() *types.Signature {
+	web := types.NewPackage("github.com/beego/beego/v2/server/web", "web")
+	base := types.NewNamed(types.NewTypeName(token.NoPos, web, "Controller", nil), types.NewStruct(nil, nil), nil)
+	pkg := types.NewPackage("example.com/app", "app")
+	embedded := types.NewField(token.NoPos, pkg, "Controller", base, true)
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "MainController", nil),
+		types.NewStruct([]*types.Var{embedded}, nil), nil)
+	recv := types.NewVar(token.NoPos, pkg, "c", types.NewPointer(named))
+	return types.NewSignatureType(recv, nil, nil, nil, nil, false)
+}
+
+func TestMatchFields(t *testing.T) {
+	r := &Rule{Fields: []string{"github.com/beego/beego/beego.Controller", "github.com/beego/beego/v2/server/web.Controller"}}
+	if !r.Match("(*example.com/app.MainController).Get", controller(), 0) {
+		t.Error("a method of a beego controller should match")
+	}
+	if r.Match("example.com/app.Get", types.NewSignatureType(nil, nil, nil, nil, nil, false), 0) {
+		t.Error("a function without receiver should not match")
+	}
+	if r.Match("(*example.com/app.MainController).Get", nil, 0) {
+		t.Error("a function with unknown signature should not match")
+	}
+}
+
+func TestInvalidRegex(t *testing.T) {
+	pack := NewPack()
+	pack.Sinks = append(pack.Sinks, &Rule{Regex: "("}, &Rule{Function: "os/exec.Command"})
+	if pack.Compile() == nil {
+		t.Error("Compile should fail on an invalid regex")
+	}
+	if Find(pack.Sinks, "os/exec.Command", nil, 0) != pack.Sinks[1] {
+		t.Error("a rule with an invalid regex should not match")
+	}
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("sinks:\n  - regex: '('\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load should fail on an invalid regex")
+	}
+}
+
+func TestDefaultBeegoSource(t *testing.T) {
+	pack, err := Load("default.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Find(pack.Sources, "(*example.com/app.MainController).Get", controller(), 0) == nil {
+		t.Error("the receiver of a beego controller should be a source")
+	}
+}
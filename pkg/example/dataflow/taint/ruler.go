@@ -25,152 +25,142 @@ func NewDummyRuler(moduleName ...string) *DummyRuler {
 func (r *DummyRuler) IsIntra(_f any) bool {
 	switch node := (_f).(type) {
 	case *Node:
-		for _, name := range r.moduleName {
-			if strings.HasPrefix(node.Canonical, name) {
-				return true
-			} else if strings.HasPrefix(node.Canonical, "("+name) {
-				return true
-			} else if strings.HasPrefix(node.Canonical, "(*"+name) {
-				return true
-			}
-		}
+		return isIntra(node, r.moduleName)
 	}
 	return false
 }
 
+// dummySinks are sinks of DummyRuler with labels they fire on
+var dummySinks = map[string]string{
+	"os/exec.Command":                                          rule.CmdI,
+	"os/exec.CommandContext":                                   rule.CmdI,
+	"syscall.Exec":                                             rule.CmdI,
+	"syscall.ForkExec":                                         rule.CmdI,
+	"syscall.StartProcess":                                     rule.CmdI,
+	"(*database/sql.DB).Exec":                                  rule.SQLi,
+	"(*database/sql.DB).ExecContext":                           rule.SQLi,
+	"(*database/sql.DB).Query":                                 rule.SQLi,
+	"(*database/sql.DB).QueryContext":                          rule.SQLi,
+	"(*database/sql.DB).QueryRow":                              rule.SQLi,
+	"(*database/sql.DB).QueryRowContext":                       rule.SQLi,
+	"(*database/sql.Stmt).Exec":                                rule.SQLi,
+	"(*database/sql.Stmt).ExecContext":                         rule.SQLi,
+	"(*database/sql.Stmt).Query":                               rule.SQLi,
+	"(*database/sql.Stmt).QueryContext":                        rule.SQLi,
+	"(*database/sql.Stmt).QueryRow":                            rule.SQLi,
+	"(*database/sql.Stmt).QueryRowContext":                     rule.SQLi,
+	"(*database/sql.Tx).Exec":                                  rule.SQLi,
+	"(*database/sql.Tx).ExecContext":                           rule.SQLi,
+	"(*database/sql.Tx).Query":                                 rule.SQLi,
+	"(*database/sql.Tx).QueryContext":                          rule.SQLi,
+	"(*database/sql.Tx).QueryRow":                              rule.SQLi,
+	"(*database/sql.Tx).QueryRowContext":                       rule.SQLi,
+	"(*github.com/jmoiron/sqlx.DB).Select":                     rule.SQLi,
+	"(*github.com/jmoiron/sqlx.DB).Get":                        rule.SQLi,
+	"(*github.com/jmoiron/sqlx.DB).Queryx":                     rule.SQLi,
+	"(*github.com/jmoiron/sqlx.DB).QueryRowx":                  rule.SQLi,
+	"(*gorm.io/gorm.DB).Raw":                                   rule.SQLi,
+	"(*gorm.io/gorm.DB).Where":                                 rule.SQLi,
+	"(*gorm.io/gorm.DB).Or":                                    rule.SQLi,
+	"(*gorm.io/gorm.DB).Order":                                 rule.SQLi,
+	"(*xorm.io/xorm.Engine).Query":                             rule.SQLi,
+	"(*xorm.io/xorm.Engine).Exec":                              rule.SQLi,
+	"(*xorm.io/xorm.Engine).QueryString":                       rule.SQLi,
+	"(*xorm.io/xorm.Engine).QueryInterface":                    rule.SQLi,
+	"(*xorm.io/xorm.Engine).Where":                             rule.SQLi,
+	"(*xorm.io/xorm.Engine).OrderBy":                           rule.SQLi,
+	"(*xorm.io/xorm.Engine).SQL":                               rule.SQLi,
+	"(*xorm.io/xorm.Session).Query":                            rule.SQLi,
+	"(*xorm.io/xorm.Session).Exec":                             rule.SQLi,
+	"(*xorm.io/xorm.Session).QuerySliceString":                 rule.SQLi,
+	"(*xorm.io/xorm.Session).QueryInterface":                   rule.SQLi,
+	"(*xorm.io/xorm.Session).And":                              rule.SQLi,
+	"(*xorm.io/xorm.Session).Or":                               rule.SQLi,
+	"(*xorm.io/xorm.Session).Where":                            rule.SQLi,
+	"(*xorm.io/xorm.Session).OrderBy":                          rule.SQLi,
+	"(*xorm.io/xorm.Session).SQL":                              rule.SQLi,
+	"(github.com/Masterminds/squirrel.SelectBuilder).From":     rule.SQLi,
+	"(github.com/Masterminds/squirrel.SelectBuilder).Where":    rule.SQLi,
+	"(github.com/Masterminds/squirrel.SelectBuilder).OrderBy":  rule.SQLi,
+	"net/http.Get":                                             rule.SSRF,
+	"net/http.Head":                                            rule.SSRF,
+	"net/http.Post":                                            rule.SSRF,
+	"net/http.PostForm":                                        rule.SSRF,
+	"(*net/http.Client).Do":                                    rule.SSRF,
+	"(*net/http.Client).Get":                                   rule.SSRF,
+	"(*net/http.Client).Head":                                  rule.SSRF,
+	"(*net/http.Client).Post":                                  rule.SSRF,
+	"(*net/http.Client).PostForm":                              rule.SSRF,
+	"(*github.com/hashicorp/go-retryablehttp.Client).Do":       rule.SSRF,
+	"(*github.com/hashicorp/go-retryablehttp.Client).Get":      rule.SSRF,
+	"(*github.com/hashicorp/go-retryablehttp.Client).Head":     rule.SSRF,
+	"(*github.com/hashicorp/go-retryablehttp.Client).Post":     rule.SSRF,
+	"(*github.com/hashicorp/go-retryablehttp.Client).PostForm": rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Get":              rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Post":             rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Put":              rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Delete":           rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Options":          rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Patch":            rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Send":             rule.SSRF,
+	"(*github.com/go-resty/resty/v2.Request).Execute":          rule.SSRF,
+	"github.com/sethgrid/pester.Do":                            rule.SSRF,
+	"github.com/sethgrid/pester.Get":                           rule.SSRF,
+	"github.com/sethgrid/pester.Head":                          rule.SSRF,
+	"github.com/sethgrid/pester.Post":                          rule.SSRF,
+	"github.com/sethgrid/pester.PostForm":                      rule.SSRF,
+	"(*github.com/sethgrid/pester.Client).Do":                  rule.SSRF,
+	"(*github.com/sethgrid/pester.Client).Get":                 rule.SSRF,
+	"(*github.com/sethgrid/pester.Client).Head":                rule.SSRF,
+	"(*github.com/sethgrid/pester.Client).Post":                rule.SSRF,
+	"(*github.com/sethgrid/pester.Client).PostForm":            rule.SSRF,
+	"(*github.com/imroc/req.Request).SetURL":                   rule.SSRF,
+	"(*github.com/dghubble/sling).Base":                        rule.SSRF,
+	"(*github.com/dghubble/sling).Get":                         rule.SSRF,
+	"(*github.com/dghubble/sling).Head":                        rule.SSRF,
+	"(*github.com/dghubble/sling).Post":                        rule.SSRF,
+	"(*github.com/dghubble/sling).Put":                         rule.SSRF,
+	"(*github.com/dghubble/sling).Patch":                       rule.SSRF,
+	"(*github.com/dghubble/sling).Delete":                      rule.SSRF,
+	"(*github.com/dghubble/sling).Options":                     rule.SSRF,
+	"(*github.com/dghubble/sling).Trace":                       rule.SSRF,
+	"(*github.com/dghubble/sling).Connect":                     rule.SSRF,
+	"github.com/asmcos/requests.Get":                           rule.SSRF,
+	"github.com/asmcos/requests.Post":                          rule.SSRF,
+	"github.com/asmcos/requests.PostJson":                      rule.SSRF,
+	"(*github.com/asmcos/requests.Request).Get":                rule.SSRF,
+	"(*github.com/asmcos/requests.Request).Post":               rule.SSRF,
+	"(*github.com/asmcos/requests.Request).PostJson":           rule.SSRF,
+	"github.com/carlmjohnson/requests.URL":                     rule.SSRF,
+	"(*github.com/carlmjohnson/requests.Builder).Host":         rule.SSRF,
+	"(*github.com/carlmjohnson/requests.Builder).Do":           rule.SSRF,
+	"github.com/mozillazg/request.Get":                         rule.SSRF,
+	"github.com/mozillazg/request.Head":                        rule.SSRF,
+	"github.com/mozillazg/request.Post":                        rule.SSRF,
+	"github.com/mozillazg/request.Put":                         rule.SSRF,
+	"github.com/mozillazg/request.Patch":                       rule.SSRF,
+	"github.com/mozillazg/request.Delete":                      rule.SSRF,
+	"github.com/mozillazg/request.Options":                     rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Get":              rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Head":             rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Post":             rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Put":              rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Patch":            rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Delete":           rule.SSRF,
+	"(*github.com/mozillazg/request.Request).Options":          rule.SSRF,
+	"os.Create":           rule.PathTraversal,
+	"os.Open":             rule.PathTraversal,
+	"os.OpenFile":         rule.PathTraversal,
+	"os.ReadFile":         rule.PathTraversal,
+	"io/ioutil.ReadFile":  rule.PathTraversal,
+	"io/ioutil.WriteFile": rule.PathTraversal,
+}
+
 // IsSink returns whether a node is a sink
 func (r *DummyRuler) IsSink(_f any) bool {
-	sink := make(map[string]bool)
-	// cmdi
-	sink["os/exec.Command"] = true
-	sink["os/exec.CommandContext"] = true
-	sink["syscall.Exec"] = true
-	sink["syscall.ForkExec"] = true
-	sink["syscall.StartProcess"] = true
-	// sqli
-	sink["(*database/sql.DB).Exec"] = true
-	sink["(*database/sql.DB).ExecContext"] = true
-	sink["(*database/sql.DB).Query"] = true
-	sink["(*database/sql.DB).QueryContext"] = true
-	sink["(*database/sql.DB).QueryRow"] = true
-	sink["(*database/sql.DB).QueryRowContext"] = true
-	sink["(*database/sql.Stmt).Exec"] = true
-	sink["(*database/sql.Stmt).ExecContext"] = true
-	sink["(*database/sql.Stmt).Query"] = true
-	sink["(*database/sql.Stmt).QueryContext"] = true
-	sink["(*database/sql.Stmt).QueryRow"] = true
-	sink["(*database/sql.Stmt).QueryRowContext"] = true
-	sink["(*database/sql.Tx).Exec"] = true
-	sink["(*database/sql.Tx).ExecContext"] = true
-	sink["(*database/sql.Tx).Query"] = true
-	sink["(*database/sql.Tx).QueryContext"] = true
-	sink["(*database/sql.Tx).QueryRow"] = true
-	sink["(*database/sql.Tx).QueryRowContext"] = true
-	sink["(*github.com/jmoiron/sqlx.DB).Select"] = true
-	sink["(*github.com/jmoiron/sqlx.DB).Get"] = true
-	sink["(*github.com/jmoiron/sqlx.DB).Queryx"] = true
-	sink["(*github.com/jmoiron/sqlx.DB).QueryRowx"] = true
-	sink["(*gorm.io/gorm.DB).Raw"] = true
-	sink["(*gorm.io/gorm.DB).Where"] = true
-	sink["(*gorm.io/gorm.DB).Or"] = true
-	sink["(*gorm.io/gorm.DB).Order"] = true
-	sink["(*xorm.io/xorm.Engine).Query"] = true
-	sink["(*xorm.io/xorm.Engine).Exec"] = true
-	sink["(*xorm.io/xorm.Engine).QueryString"] = true
-	sink["(*xorm.io/xorm.Engine).QueryInterface"] = true
-	sink["(*xorm.io/xorm.Engine).Where"] = true
-	sink["(*xorm.io/xorm.Engine).OrderBy"] = true
-	sink["(*xorm.io/xorm.Engine).SQL"] = true
-	sink["(*xorm.io/xorm.Session).Query"] = true
-	sink["(*xorm.io/xorm.Session).Exec"] = true
-	sink["(*xorm.io/xorm.Session).QuerySliceString"] = true
-	sink["(*xorm.io/xorm.Session).QueryInterface"] = true
-	sink["(*xorm.io/xorm.Session).And"] = true
-	sink["(*xorm.io/xorm.Session).Or"] = true
-	sink["(*xorm.io/xorm.Session).Where"] = true
-	sink["(*xorm.io/xorm.Session).OrderBy"] = true
-	sink["(*xorm.io/xorm.Session).SQL"] = true
-	sink["(github.com/Masterminds/squirrel.SelectBuilder).From"] = true
-	sink["(github.com/Masterminds/squirrel.SelectBuilder).Where"] = true
-	sink["(github.com/Masterminds/squirrel.SelectBuilder).OrderBy"] = true
-	// ssrf
-	sink["net/http.Get"] = true
-	sink["net/http.Head"] = true
-	sink["net/http.Post"] = true
-	sink["net/http.PostForm"] = true
-	sink["(*net/http.Client).Do"] = true
-	sink["(*net/http.Client).Get"] = true
-	sink["(*net/http.Client).Head"] = true
-	sink["(*net/http.Client).Post"] = true
-	sink["(*net/http.Client).PostForm"] = true
-	sink["(*github.com/hashicorp/go-retryablehttp.Client).Do"] = true
-	sink["(*github.com/hashicorp/go-retryablehttp.Client).Get"] = true
-	sink["(*github.com/hashicorp/go-retryablehttp.Client).Head"] = true
-	sink["(*github.com/hashicorp/go-retryablehttp.Client).Post"] = true
-	sink["(*github.com/hashicorp/go-retryablehttp.Client).PostForm"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Get"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Post"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Put"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Delete"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Options"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Patch"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Send"] = true
-	sink["(*github.com/go-resty/resty/v2.Request).Execute"] = true
-	sink["github.com/sethgrid/pester.Do"] = true
-	sink["github.com/sethgrid/pester.Get"] = true
-	sink["github.com/sethgrid/pester.Head"] = true
-	sink["github.com/sethgrid/pester.Post"] = true
-	sink["github.com/sethgrid/pester.PostForm"] = true
-	sink["(*github.com/sethgrid/pester.Client).Do"] = true
-	sink["(*github.com/sethgrid/pester.Client).Get"] = true
-	sink["(*github.com/sethgrid/pester.Client).Head"] = true
-	sink["(*github.com/sethgrid/pester.Client).Post"] = true
-	sink["(*github.com/sethgrid/pester.Client).PostForm"] = true
-	sink["(*github.com/imroc/req.Request).SetURL"] = true
-	sink["(*github.com/dghubble/sling).Base"] = true
-	sink["(*github.com/dghubble/sling).Get"] = true
-	sink["(*github.com/dghubble/sling).Head"] = true
-	sink["(*github.com/dghubble/sling).Post"] = true
-	sink["(*github.com/dghubble/sling).Put"] = true
-	sink["(*github.com/dghubble/sling).Patch"] = true
-	sink["(*github.com/dghubble/sling).Delete"] = true
-	sink["(*github.com/dghubble/sling).Options"] = true
-	sink["(*github.com/dghubble/sling).Trace"] = true
-	sink["(*github.com/dghubble/sling).Connect"] = true
-	sink["github.com/asmcos/requests.Get"] = true
-	sink["github.com/asmcos/requests.Post"] = true
-	sink["github.com/asmcos/requests.PostJson"] = true
-	sink["(*github.com/asmcos/requests.Request).Get"] = true
-	sink["(*github.com/asmcos/requests.Request).Post"] = true
-	sink["(*github.com/asmcos/requests.Request).PostJson"] = true
-	sink["github.com/carlmjohnson/requests.URL"] = true
-	sink["(*github.com/carlmjohnson/requests.Builder).Host"] = true
-	sink["(*github.com/carlmjohnson/requests.Builder).Do"] = true
-	sink["github.com/mozillazg/request.Get"] = true
-	sink["github.com/mozillazg/request.Head"] = true
-	sink["github.com/mozillazg/request.Post"] = true
-	sink["github.com/mozillazg/request.Put"] = true
-	sink["github.com/mozillazg/request.Patch"] = true
-	sink["github.com/mozillazg/request.Delete"] = true
-	sink["github.com/mozillazg/request.Options"] = true
-	sink["(*github.com/mozillazg/request.Request).Get"] = true
-	sink["(*github.com/mozillazg/request.Request).Head"] = true
-	sink["(*github.com/mozillazg/request.Request).Post"] = true
-	sink["(*github.com/mozillazg/request.Request).Put"] = true
-	sink["(*github.com/mozillazg/request.Request).Patch"] = true
-	sink["(*github.com/mozillazg/request.Request).Delete"] = true
-	sink["(*github.com/mozillazg/request.Request).Options"] = true
-	// traversal
-	sink["os.Create"] = true
-	sink["os.Open"] = true
-	sink["os.OpenFile"] = true
-	sink["os.ReadFile"] = true
-	sink["io/ioutil.ReadFile"] = true
-	sink["io/ioutil.WriteFile"] = true
-
 	switch node := _f.(type) {
 	case *Node:
-		_, ok := sink[node.Canonical]
+		_, ok := dummySinks[node.Canonical]
 		if ok {
 			return true
 		}
@@ -178,10 +168,49 @@ func (r *DummyRuler) IsSink(_f any) bool {
 	return false
 }
 
+// dummySources are functions whose results and fields whose reads are sources in DummyRuler
+// a field is named like a method with a value receiver
+var dummySources = map[string]bool{
+	"os.Getenv":                                        true,
+	"os.LookupEnv":                                     true,
+	"(*net/http.Request).FormValue":                    true,
+	"(*net/http.Request).PostFormValue":                true,
+	"(*net/http.Request).Cookie":                       true,
+	"(*net/http.Request).Cookies":                      true,
+	"(*net/http.Request).Referer":                      true,
+	"(*net/http.Request).UserAgent":                    true,
+	"(*net/http.Request).FormFile":                     true,
+	"(net/http.Request).Body":                          true,
+	"(net/http.Request).Form":                          true,
+	"(net/http.Request).PostForm":                      true,
+	"(net/http.Request).MultipartForm":                 true,
+	"(net/http.Request).Header":                        true,
+	"(net/http.Request).URL":                           true,
+	"(net/http.Request).Host":                          true,
+	"(net/http.Request).RequestURI":                    true,
+	"(*github.com/gin-gonic/gin.Context).Query":        true,
+	"(*github.com/gin-gonic/gin.Context).DefaultQuery": true,
+	"(*github.com/gin-gonic/gin.Context).GetQuery":     true,
+	"(*github.com/gin-gonic/gin.Context).PostForm":     true,
+	"(*github.com/gin-gonic/gin.Context).GetPostForm":  true,
+	"(*github.com/gin-gonic/gin.Context).Param":        true,
+	"(*github.com/gin-gonic/gin.Context).GetHeader":    true,
+	"(*github.com/gin-gonic/gin.Context).GetRawData":   true,
+	"(*bufio.Scanner).Text":                            true,
+	"(*bufio.Scanner).Bytes":                           true,
+	"(*bufio.Reader).ReadString":                       true,
+	"(*bufio.Reader).ReadLine":                         true,
+}
+
 // IsSource returns whether a node is a source
+// parameters of handlers are sources, and results of a function or reads of a field in dummySources are sources,
+// which are asked by rule.ResultIndex
 func (r *DummyRuler) IsSource(_f any) bool {
 	switch node := _f.(type) {
 	case *Node:
+		if node.Index == rule.ResultIndex {
+			return dummySources[node.Canonical]
+		}
 		if node.Function != nil {
 			flag := false
 			f := node.Function
@@ -194,6 +223,68 @@ func (r *DummyRuler) IsSource(_f any) bool {
 	return false
 }
 
+// dummySanitizers are functions whose results are sanitized in DummyRuler, with labels they remove
+// an empty label means all labels are removed
+var dummySanitizers = map[string]string{
+	"strconv.Atoi":                   "",
+	"strconv.ParseBool":              "",
+	"strconv.ParseFloat":             "",
+	"strconv.ParseInt":               "",
+	"strconv.ParseUint":              "",
+	"html.EscapeString":              rule.XSS,
+	"html/template.HTMLEscapeString": rule.XSS,
+	"html/template.JSEscapeString":   rule.XSS,
+	"net/url.PathEscape":             rule.SSRF,
+	"net/url.QueryEscape":            rule.SSRF,
+	"path.Base":                      rule.PathTraversal,
+	"path/filepath.Base":             rule.PathTraversal,
+}
+
+// IsSanitizer returns whether a node is a sanitizer
+// only results of functions in dummySanitizers are sanitized
+func (r *DummyRuler) IsSanitizer(_f any) bool {
+	switch node := _f.(type) {
+	case *Node:
+		if node.Index == rule.ResultIndex {
+			_, ok := dummySanitizers[node.Canonical]
+			return ok
+		}
+	}
+	return false
+}
+
+// Labels returns default labels
+func (r *DummyRuler) Labels() []string {
+	return rule.Labels
+}
+
+// SourceLabels returns labels emitted by a source, sources emit all labels
+func (r *DummyRuler) SourceLabels(_f any) []string {
+	return nil
+}
+
+// SinkLabels returns labels a sink fires on
+func (r *DummyRuler) SinkLabels(_f any) []string {
+	switch node := _f.(type) {
+	case *Node:
+		if label, ok := dummySinks[node.Canonical]; ok {
+			return []string{label}
+		}
+	}
+	return nil
+}
+
+// SanitizerLabels returns labels removed by a sanitizer
+func (r *DummyRuler) SanitizerLabels(_f any) []string {
+	switch node := _f.(type) {
+	case *Node:
+		if label := dummySanitizers[node.Canonical]; label != "" && node.Index == rule.ResultIndex {
+			return []string{label}
+		}
+	}
+	return nil
+}
+
 func checkTrivalHandler(f *ssa.Function) bool {
 	hit := 0
 	for _, param := range f.Params {
@@ -255,7 +346,22 @@ func checkGinHandler(f *ssa.Function) bool {
 	return false
 }
 
+// isIntra returns whether a node is from one of the modules
+func isIntra(node *Node, moduleName []string) bool {
+	for _, name := range moduleName {
+		if strings.HasPrefix(node.Canonical, name) {
+			return true
+		} else if strings.HasPrefix(node.Canonical, "("+name) {
+			return true
+		} else if strings.HasPrefix(node.Canonical, "(*"+name) {
+			return true
+		}
+	}
+	return false
+}
+
 // passPropertry pass properties from a node to an edge
+// an edge goes to a sink only if it carries a label of the sink, or the implicit label
 func passProperty(node *Node, edge *Edge) {
 	if node.IsMethod {
 		edge.ToIsMethod = true
@@ -264,7 +370,7 @@ func passProperty(node *Node, edge *Edge) {
 	} else if node.IsSignature {
 		edge.ToIsSignature = true
 	}
-	if node.IsSink {
+	if node.IsSink && len(rule.Intersect(edge.Labels, rule.Union(node.Labels, []string{rule.Implicit}))) != 0 {
 		edge.ToIsSink = true
 	}
 }
@@ -276,8 +382,10 @@ func decidePropertry(node *Node, ruler rule.Ruler) {
 	}
 	if ruler.IsSource(node) {
 		node.IsSource = true
+		node.Labels = rule.SourceLabelsOf(ruler, node)
 	}
 	if ruler.IsSink(node) {
 		node.IsSink = true
+		node.Labels = rule.Union(node.Labels, rule.SinkLabelsOf(ruler, node))
 	}
 }
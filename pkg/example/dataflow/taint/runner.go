@@ -1,10 +1,9 @@
 package taint
 
 import (
-	"container/list"
-
 	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
 	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/pointer"
 	"golang.org/x/tools/go/ssa"
@@ -22,24 +21,35 @@ type Runner struct {
 	PassThroughSrcPath []string
 	PassThroughDstPath string
 	TaintGraphDstPath  string
+	FindingDepth       int
+	FindingDstPath     string
+	SarifDstPath       string
+	Findings           []*Finding
 	Ruler              rule.Ruler
+	RuleSrcPath        []string
 	PersistToNeo4j     bool
 	Neo4jUsername      string
 	Neo4jPassword      string
 	Neo4jURI           string
 	TargetFunc         string
+	TraceDstDir        string
+	GraphDstDir        string
 	PassBack           bool
+	AccessPathDepth    int
+	ContextSensitive   bool
+	UseRTA             bool
+	ImplicitFlow       bool
 }
 
 // NewRunner returns a *taint.Runner
 func NewRunner(PkgPath ...string) *Runner {
 	return &Runner{PkgPath: PkgPath, ModuleName: "",
 		PassThroughSrcPath: nil, PassThroughDstPath: "",
-		TaintGraphDstPath: "", Ruler: nil,
+		TaintGraphDstPath: "", FindingDepth: 10, FindingDstPath: "", SarifDstPath: "", Ruler: nil, RuleSrcPath: nil,
 		Debug: false, InitOnly: false, PassThroughOnly: false,
 		PersistToNeo4j: false, Neo4jURI: "", Neo4jUsername: "", Neo4jPassword: "",
-		TargetFunc: "", PassBack: false,
-		UsePointerAnalysis: false}
+		TargetFunc: "", TraceDstDir: "", GraphDstDir: "", PassBack: false, AccessPathDepth: 0, ContextSensitive: false,
+		UseRTA: false, ImplicitFlow: false, UsePointerAnalysis: false}
 }
 
 // Run kick off an analysis
@@ -65,6 +75,9 @@ func (r *Runner) Run() error {
 	funcs := ssautil.AllFunctions(prog)
 
 	interfaceHierarchy := NewInterfaceHierarchy(&funcs)
+	if r.UseRTA {
+		interfaceHierarchy.FilterByRTA(&funcs)
+	}
 
 	var callGraph *callgraph.Graph
 	if r.UsePointerAnalysis {
@@ -94,6 +107,11 @@ func (r *Runner) Run() error {
 	var ruler rule.Ruler
 	if r.Ruler != nil {
 		ruler = r.Ruler
+	} else if r.RuleSrcPath != nil {
+		ruler, err = LoadConfigRuler(r.RuleSrcPath, r.ModuleName)
+		if err != nil {
+			return err
+		}
 	} else {
 		ruler = NewDummyRuler(r.ModuleName)
 	}
@@ -102,15 +120,13 @@ func (r *Runner) Run() error {
 	passThroughContainter := make(map[string]*PassThroughCache)
 	if r.PassThroughSrcPath != nil {
 		FetchPassThrough(&passThroughContainter, r.PassThroughSrcPath)
+		SanitizePassThrough(&passThroughContainter, ruler)
 	}
 
 	initMap := make(map[string]*ssa.Function)
-	history := make(map[string]bool)
 
 	c := &TaintConfig{PassThroughContainer: &passThroughContainter,
 		InitMap:            &initMap,
-		History:            &history,
-		CallStack:          list.New().Init(),
 		InterfaceHierarchy: interfaceHierarchy,
 		TaintGraph:         taintGraph,
 		UsePointerAnalysis: r.UsePointerAnalysis,
@@ -119,30 +135,58 @@ func (r *Runner) Run() error {
 		PassThroughOnly:    r.PassThroughOnly,
 		Debug:              r.Debug,
 		TargetFunc:         r.TargetFunc,
-		PassBack:           r.PassBack}
+		TraceDstDir:        r.TraceDstDir,
+		GraphDstDir:        r.GraphDstDir,
+		PassBack:           r.PassBack,
+		AccessPathDepth:    r.AccessPathDepth,
+		ContextSensitive:   r.ContextSensitive,
+		ImplicitFlow:       r.ImplicitFlow}
+
+	// schedule functions by pointer analysis's call graph if it exists, else by CHA
+	scheduleGraph := callGraph
+	if scheduleGraph == nil {
+		scheduleGraph = cha.CallGraph(prog)
+	}
+	scheduler := NewScheduler(scheduleGraph, c)
 
+	inits := make([]*ssa.Function, 0)
+	others := make([]*ssa.Function, 0)
 	for f := range funcs {
 		if f.Name() == "init" {
-			Run(f, c)
+			inits = append(inits, f)
+		} else if r.TargetFunc == "" || f.String() == r.TargetFunc {
+			others = append(others, f)
 		}
 	}
 
+	// analyze init functions first to record global anonymous functions
+	scheduler.Schedule(inits)
 	if !r.InitOnly {
-		for f := range funcs {
-			if f.String() != "init" {
-				if r.TargetFunc != "" && f.String() != r.TargetFunc {
-					continue
-				}
-				Run(f, c)
-			}
-		}
+		scheduler.Schedule(others)
 	}
 
 	if r.PassThroughDstPath != "" {
-		PersistPassThrough(&passThroughContainter, r.PassThroughDstPath)
+		if err := PersistPassThrough(&passThroughContainter, r.PassThroughDstPath); err != nil {
+			return err
+		}
 	}
 	if r.TaintGraphDstPath != "" {
-		PersistTaintGraph(taintGraph.Edges, r.TaintGraphDstPath)
+		if err := PersistTaintGraph(taintGraph.Edges, r.TaintGraphDstPath); err != nil {
+			return err
+		}
+	}
+	if !r.PassThroughOnly {
+		r.Findings = FindPaths(taintGraph, r.FindingDepth)
+		if r.FindingDstPath != "" {
+			if err := PersistFindings(r.Findings, r.FindingDstPath); err != nil {
+				return err
+			}
+		}
+		if r.SarifDstPath != "" {
+			if err := PersistSarif(r.Findings, taintGraph, prog.Fset, r.SarifDstPath); err != nil {
+				return err
+			}
+		}
 	}
 	if !r.PassThroughOnly && r.PersistToNeo4j {
 		PersistToNeo4j(taintGraph.Nodes, taintGraph.Edges, r.Neo4jURI, r.Neo4jUsername, r.Neo4jPassword)
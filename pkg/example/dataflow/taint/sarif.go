@@ -0,0 +1,222 @@
+package taint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+)
+
+// SarifLog represents a SARIF 2.1.0 log
+type SarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*SarifRun `json:"runs"`
+}
+
+// SarifRun represents a run of a tool in a SARIF log
+type SarifRun struct {
+	Tool    *SarifTool     `json:"tool"`
+	Results []*SarifResult `json:"results"`
+}
+
+// SarifTool represents a tool in a SARIF log
+type SarifTool struct {
+	Driver *SarifDriver `json:"driver"`
+}
+
+// SarifDriver represents the driver of a tool and its rules
+type SarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*SarifRule `json:"rules"`
+}
+
+// SarifRule represents a rule of a tool, there is a rule for a sink category
+type SarifRule struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	ShortDescription *SarifMessage  `json:"shortDescription"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+// SarifMessage represents a message in a SARIF log
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult represents a finding of a rule
+type SarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             *SarifMessage     `json:"message"`
+	Locations           []*SarifLocation  `json:"locations"`
+	CodeFlows           []*SarifCodeFlow  `json:"codeFlows"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+// SarifLocation represents a location in source code
+type SarifLocation struct {
+	PhysicalLocation *SarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *SarifMessage          `json:"message,omitempty"`
+}
+
+// SarifPhysicalLocation represents a file and a region in it
+type SarifPhysicalLocation struct {
+	ArtifactLocation *SarifArtifactLocation `json:"artifactLocation"`
+	Region           *SarifRegion           `json:"region,omitempty"`
+}
+
+// SarifArtifactLocation represents a file
+type SarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SarifRegion represents a region in a file
+type SarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// SarifCodeFlow represents a path of a result
+type SarifCodeFlow struct {
+	ThreadFlows []*SarifThreadFlow `json:"threadFlows"`
+}
+
+// SarifThreadFlow represents hops of a path
+type SarifThreadFlow struct {
+	Locations []*SarifThreadFlowLocation `json:"locations"`
+}
+
+// SarifThreadFlowLocation represents a hop of a path
+type SarifThreadFlowLocation struct {
+	Location *SarifLocation `json:"location"`
+}
+
+// sarifDescriptions are descriptions of default labels
+var sarifDescriptions = map[string]string{
+	rule.CmdI:          "Command injection",
+	rule.PathTraversal: "Path traversal",
+	rule.SQLi:          "SQL injection",
+	rule.SSRF:          "Server-side request forgery",
+	rule.XSS:           "Cross-site scripting",
+	rule.Implicit:      "Implicit flow by a tainted condition",
+}
+
+// NewSarifLog returns a SarifLog of findings
+// there is a result for a finding and each of its labels, locations are calls creating edges of its path
+func NewSarifLog(findings []*Finding, taintGraph *TaintGraph, fset *token.FileSet) *SarifLog {
+	driver := &SarifDriver{Name: "goot", InformationURI: "https://github.com/cokeBeer/goot", Rules: make([]*SarifRule, 0)}
+	run := &SarifRun{Tool: &SarifTool{Driver: driver}, Results: make([]*SarifResult, 0)}
+	ruleIndex := make(map[string]int)
+	for _, finding := range findings {
+		for _, label := range finding.Labels {
+			id := "goot/" + label
+			if _, ok := ruleIndex[id]; !ok {
+				description, ok := sarifDescriptions[label]
+				if !ok {
+					description = "Taint of category " + label
+				}
+				ruleIndex[id] = len(driver.Rules)
+				driver.Rules = append(driver.Rules, &SarifRule{ID: id, Name: label,
+					ShortDescription: &SarifMessage{Text: description},
+					Properties:       map[string]any{"tags": []string{"security", label}}})
+			}
+			run.Results = append(run.Results, newSarifResult(finding, label, id, ruleIndex[id], taintGraph, fset))
+		}
+	}
+	return &SarifLog{Schema: "https://json.schemastore.org/sarif-2.1.0.json", Version: "2.1.0", Runs: []*SarifRun{run}}
+}
+
+// newSarifResult returns a result of a finding with a label
+func newSarifResult(finding *Finding, label string, id string, index int, taintGraph *TaintGraph, fset *token.FileSet) *SarifResult {
+	threadFlow := &SarifThreadFlow{Locations: make([]*SarifThreadFlowLocation, 0)}
+	for _, edge := range finding.Path {
+		location := sarifLocation(edgePosition(taintGraph, edge, fset))
+		location.Message = &SarifMessage{Text: fmt.Sprintf("%s#%d flows to %s#%d", edge.From, edge.FromIndex, edge.To, edge.ToIndex)}
+		threadFlow.Locations = append(threadFlow.Locations, &SarifThreadFlowLocation{Location: location})
+	}
+	// the sink is called by the last hop
+	sink := sarifLocation(token.Position{})
+	if n := len(finding.Path); n != 0 {
+		sink = sarifLocation(edgePosition(taintGraph, finding.Path[n-1], fset))
+	}
+	message := fmt.Sprintf("%s taint from %s#%d reaches sink %s#%d", label, finding.Source, finding.SourceIndex, finding.Sink, finding.SinkIndex)
+	return &SarifResult{RuleID: id, RuleIndex: index, Level: "error",
+		Message:             &SarifMessage{Text: message},
+		Locations:           []*SarifLocation{sink},
+		CodeFlows:           []*SarifCodeFlow{{ThreadFlows: []*SarifThreadFlow{threadFlow}}},
+		PartialFingerprints: map[string]string{"gootFinding/v1": fingerprint(finding, label)},
+		Properties:          map[string]any{"pathHash": pathHash(finding)}}
+}
+
+// edgePosition returns position of an edge, which is the call creating it
+// it falls back to the declaration of the function it goes from, like edges loaded without positions
+func edgePosition(taintGraph *TaintGraph, edge *Edge, fset *token.FileSet) token.Position {
+	if edge.Position.IsValid() {
+		return edge.Position
+	}
+	node, ok := (*taintGraph.Nodes)[edge.From+"#"+strconv.Itoa(edge.FromIndex)]
+	if !ok || node.Function == nil || fset == nil {
+		return token.Position{}
+	}
+	return fset.Position(node.Function.Pos())
+}
+
+// sarifLocation returns a location of a position, a file under working directory is relative to %SRCROOT%
+func sarifLocation(position token.Position) *SarifLocation {
+	if !position.IsValid() {
+		return &SarifLocation{}
+	}
+	artifact := &SarifArtifactLocation{URI: filepath.ToSlash(position.Filename)}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, position.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			artifact = &SarifArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: "%SRCROOT%"}
+		}
+	}
+	return &SarifLocation{PhysicalLocation: &SarifPhysicalLocation{ArtifactLocation: artifact,
+		Region: &SarifRegion{StartLine: position.Line, StartColumn: position.Column}}}
+}
+
+// fingerprint returns a hash of the label, the source and the sink of a finding,
+// which is stable when lines of code move or functions between the source and the sink change
+func fingerprint(finding *Finding, label string) string {
+	key := fmt.Sprintf("%s|%s#%d|%s#%d", label, finding.Source, finding.SourceIndex, finding.Sink, finding.SinkIndex)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// pathHash returns a hash of edges in the path of a finding, which tells apart paths of the same finding
+func pathHash(finding *Finding) string {
+	var b strings.Builder
+	for _, edge := range finding.Path {
+		fmt.Fprintf(&b, "%s#%d>%s#%d|", edge.From, edge.FromIndex, edge.To, edge.ToIndex)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersistSarif stores findings to target destination in SARIF
+func PersistSarif(findings []*Finding, taintGraph *TaintGraph, fset *token.FileSet, dst string) error {
+	f, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := json.Marshal(NewSarifLog(findings, taintGraph, fset))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, string(res))
+	return err
+}
@@ -0,0 +1,29 @@
+package taint
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	direct := &Finding{Source: "pkg.Handler", SourceIndex: 1, Sink: "os/exec.Command", SinkIndex: 0, Path: []*Edge{
+		{From: "pkg.Handler", FromIndex: 1, To: "pkg.run", ToIndex: 0},
+		{From: "pkg.run", FromIndex: 0, To: "os/exec.Command", ToIndex: 0},
+	}}
+	refactored := &Finding{Source: "pkg.Handler", SourceIndex: 1, Sink: "os/exec.Command", SinkIndex: 0, Path: []*Edge{
+		{From: "pkg.Handler", FromIndex: 1, To: "pkg.clean", ToIndex: 0},
+		{From: "pkg.clean", FromIndex: 0, To: "pkg.run", ToIndex: 0},
+		{From: "pkg.run", FromIndex: 0, To: "os/exec.Command", ToIndex: 0},
+	}}
+	if fingerprint(direct, "cmdi") != fingerprint(refactored, "cmdi") {
+		t.Error("a fingerprint should not change with hops in the middle of a path")
+	}
+	if pathHash(direct) == pathHash(refactored) {
+		t.Error("a path hash should change with hops in the middle of a path")
+	}
+	if fingerprint(direct, "cmdi") == fingerprint(direct, "sqli") {
+		t.Error("a fingerprint should change with the label")
+	}
+	other := *direct
+	other.SinkIndex = 1
+	if fingerprint(direct, "cmdi") == fingerprint(&other, "cmdi") {
+		t.Error("a fingerprint should change with the index of the sink")
+	}
+}
@@ -0,0 +1,242 @@
+package taint
+
+import (
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dnote/color"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxSCCRounds limits rounds of iteration on a recursive SCC
+const maxSCCRounds = 20
+
+// Scheduler schedules taint analysis on functions bottom-up by a call graph
+type Scheduler struct {
+	callGraph *callgraph.Graph
+	config    *TaintConfig
+	index     map[*ssa.Function]int
+	lowLink   map[*ssa.Function]int
+	onStack   map[*ssa.Function]bool
+	stack     []*ssa.Function
+	next      int
+}
+
+// frame represents a function being visited in Scheduler.visit
+type frame struct {
+	f       *ssa.Function
+	callees []*ssa.Function
+	i       int
+}
+
+// NewScheduler returns a Scheduler
+func NewScheduler(callGraph *callgraph.Graph, c *TaintConfig) *Scheduler {
+	scheduler := new(Scheduler)
+	scheduler.callGraph = callGraph
+	scheduler.config = c
+	scheduler.index = make(map[*ssa.Function]int)
+	scheduler.lowLink = make(map[*ssa.Function]int)
+	scheduler.onStack = make(map[*ssa.Function]bool)
+	scheduler.stack = make([]*ssa.Function, 0)
+	return scheduler
+}
+
+// Schedule runs taint analysis on funcs and all their callees
+// strongly connected components of the call graph are analyzed in reverse topological order,
+// so summaries of callees are ready before their callers are analyzed
+func (s *Scheduler) Schedule(funcs []*ssa.Function) {
+	roots := make([]*ssa.Function, len(funcs))
+	copy(roots, funcs)
+	sortFuncs(roots)
+	for _, f := range roots {
+		if _, ok := s.index[f]; !ok {
+			s.visit(f)
+		}
+	}
+}
+
+// visit runs an iterative tarjan algorithm from root
+// every time a SCC is found, it is analyzed at once
+func (s *Scheduler) visit(root *ssa.Function) {
+	frames := []*frame{s.push(root)}
+	for len(frames) != 0 {
+		top := frames[len(frames)-1]
+		if top.i < len(top.callees) {
+			callee := top.callees[top.i]
+			top.i++
+			if _, ok := s.index[callee]; !ok {
+				frames = append(frames, s.push(callee))
+			} else if s.onStack[callee] && s.index[callee] < s.lowLink[top.f] {
+				s.lowLink[top.f] = s.index[callee]
+			}
+			continue
+		}
+		frames = frames[:len(frames)-1]
+		if len(frames) != 0 {
+			caller := frames[len(frames)-1].f
+			if s.lowLink[top.f] < s.lowLink[caller] {
+				s.lowLink[caller] = s.lowLink[top.f]
+			}
+		}
+		if s.lowLink[top.f] == s.index[top.f] {
+			s.runSCC(s.pop(top.f))
+		}
+	}
+}
+
+// push marks a function as visited and returns its frame
+func (s *Scheduler) push(f *ssa.Function) *frame {
+	s.index[f] = s.next
+	s.lowLink[f] = s.next
+	s.next++
+	s.stack = append(s.stack, f)
+	s.onStack[f] = true
+	return &frame{f: f, callees: s.callees(f), i: 0}
+}
+
+// pop pops a SCC whose root is f from stack
+func (s *Scheduler) pop(f *ssa.Function) []*ssa.Function {
+	scc := make([]*ssa.Function, 0)
+	for {
+		w := s.stack[len(s.stack)-1]
+		s.stack = s.stack[:len(s.stack)-1]
+		s.onStack[w] = false
+		scc = append(scc, w)
+		if w == f {
+			break
+		}
+	}
+	sortFuncs(scc)
+	return scc
+}
+
+// callees returns callees of a function in the call graph
+// closures created by the function are its callees too, so their passthrough is ready when they are created
+func (s *Scheduler) callees(f *ssa.Function) []*ssa.Function {
+	callees := make([]*ssa.Function, 0)
+	seen := make(map[*ssa.Function]bool)
+	for _, b := range f.Blocks {
+		for _, instr := range b.Instrs {
+			if closure, ok := instr.(*ssa.MakeClosure); ok {
+				if callee, ok := closure.Fn.(*ssa.Function); ok && !seen[callee] {
+					seen[callee] = true
+					callees = append(callees, callee)
+				}
+			}
+		}
+	}
+	if s.callGraph == nil {
+		sortFuncs(callees)
+		return callees
+	}
+	node := s.callGraph.Nodes[f]
+	if node == nil {
+		sortFuncs(callees)
+		return callees
+	}
+	for _, edge := range node.Out {
+		callee := edge.Callee.Func
+		if callee == nil || seen[callee] {
+			continue
+		}
+		seen[callee] = true
+		callees = append(callees, callee)
+	}
+	sortFuncs(callees)
+	return callees
+}
+
+// isRecursive returns whether a SCC contains a recursive call
+func (s *Scheduler) isRecursive(scc []*ssa.Function) bool {
+	if len(scc) > 1 {
+		return true
+	}
+	for _, callee := range s.callees(scc[0]) {
+		if callee == scc[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// runSCC runs taint analysis on a SCC
+// a recursive SCC is seeded by null passthrough and iterated until its summaries stop changing
+func (s *Scheduler) runSCC(scc []*ssa.Function) {
+	c := s.config
+	if !s.isRecursive(scc) {
+		Run(scc[0], c)
+		return
+	}
+	members := make([]*ssa.Function, 0)
+	for _, f := range scc {
+		if _, ok := (*c.PassThroughContainer)[f.String()]; ok {
+			// has been recorded in passThroughContainer somewhere else, skip
+			continue
+		}
+		initNull(f, c)
+		members = append(members, f)
+	}
+	var contexts map[string]bool
+	if c.ContextSensitive {
+		contexts = contextKeys(c)
+	}
+	for round := 0; ; round++ {
+		if round >= maxSCCRounds {
+			if c.Debug {
+				color.Set(color.FgYellow)
+				log.Println("has iterated", members[0].String(), "more than max rounds, skip")
+				color.Unset()
+			}
+			return
+		}
+		changed := false
+		for _, f := range members {
+			if needNull(f, c) {
+				continue
+			}
+			old := (*c.PassThroughContainer)[f.String()]
+			doRun(f, nil, c)
+			if !reflect.DeepEqual(old, (*c.PassThroughContainer)[f.String()]) {
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+		if c.ContextSensitive {
+			// passthrough in contexts created in this round may depend on passthrough of members in the last round,
+			// including contexts of functions outside the SCC, so analyze them again
+			dropContexts(contexts, c)
+		}
+	}
+}
+
+// sortFuncs sorts functions by name to make scheduling deterministic
+func sortFuncs(funcs []*ssa.Function) {
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].String() < funcs[j].String()
+	})
+}
+
+// contextKeys returns keys of passthrough in contexts
+func contextKeys(c *TaintConfig) map[string]bool {
+	keys := make(map[string]bool)
+	for key := range *c.PassThroughContainer {
+		if strings.Contains(key, ContextSeparator) {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// dropContexts deletes passthrough in contexts except the ones kept
+func dropContexts(kept map[string]bool, c *TaintConfig) {
+	for key := range *c.PassThroughContainer {
+		if strings.Contains(key, ContextSeparator) && !kept[key] {
+			delete(*c.PassThroughContainer, key)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package taint
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
+	"golang.org/x/tools/go/ssa"
+)
+
+// SourceCarrier returns the carrier of results of a function or reads of a field which are sources, like source:os.Getenv
+// a field is named like a method with a value receiver, like (net/http.Request).Body
+func SourceCarrier(canonical string) string {
+	return "source" + CarrierSeparator + canonical
+}
+
+// fieldCanonical returns the name of the i'th field of a named struct or a pointer to it, or "" if the struct is unnamed
+func fieldCanonical(typ types.Type, i int) string {
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return "(" + named.Obj().Pkg().Path() + "." + named.Obj().Name() + ")." + fieldName(typ, i)
+}
+
+// sourceCallTaint marks results of a call with a source carrier if the ruler decides they are a source
+func (s *TaintSwitcher) sourceCallTaint(inst ssa.CallInstruction) {
+	node := calleeNode(inst)
+	if node == nil {
+		return
+	}
+	var pos token.Pos
+	if node.Function != nil {
+		pos = node.Function.Pos()
+	} else {
+		pos = inst.Common().Method.Pos()
+	}
+	s.sourceTaint(resultNames(inst), node, pos)
+}
+
+// sourceFieldTaint marks a value read from the i'th field of x with a source carrier if the ruler decides the field is a source
+func (s *TaintSwitcher) sourceFieldTaint(name string, x ssa.Value, i int) {
+	canonical := fieldCanonical(x.Type(), i)
+	if canonical == "" {
+		return
+	}
+	typ := x.Type()
+	if pointer, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	s.sourceTaint([]string{name}, &Node{Canonical: canonical}, typ.Underlying().(*types.Struct).Field(i).Pos())
+}
+
+// sourceTaint marks values with the carrier of a source, which is asked by rule.ResultIndex
+// the carrier is a source node with labels of the source, and records the declaration of the function or the field
+// sources are only introduced in functions of the module, so a library reading a request doesn't taint what it returns,
+// and not for values carrying taint from source parameters, so a flow is not found twice
+func (s *TaintSwitcher) sourceTaint(names []string, node *Node, pos token.Pos) {
+	ruler := s.taintAnalysis.config.Ruler
+	f := s.taintAnalysis.Graph.Func
+	node.Index = rule.ResultIndex
+	if !ruler.IsIntra(&Node{Function: f, Canonical: f.String()}) || !ruler.IsSource(node) {
+		return
+	}
+	carrier := carrierNode(s.taintAnalysis.config.TaintGraph, SourceCarrier(node.Canonical))
+	if !carrier.IsSource {
+		carrier.IsSource = true
+		carrier.Labels = rule.SourceLabelsOf(ruler, node)
+		carrier.Position = f.Prog.Fset.Position(pos)
+	}
+	for _, name := range names {
+		// a value from a parameter which is a source, like a field of the request of a handler, has been a finding
+		if !s.fromSourceParam(name) {
+			SetTaint(s.outMap, name, SourceCarrier(node.Canonical))
+		}
+	}
+}
+
+// fromSourceParam checks whether a value carries taint from a parameter of the analyzed function which is a source
+func (s *TaintSwitcher) fromSourceParam(name string) bool {
+	for taint := range *s.carriedTaint(name).innerTaint {
+		if IsCarrier(taint) {
+			continue
+		}
+		if node, _, ok := s.taintOrigin(taint); ok && node.IsSource {
+			return true
+		}
+	}
+	return false
+}
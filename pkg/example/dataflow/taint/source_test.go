@@ -0,0 +1,46 @@
+package taint
+
+import "testing"
+
+const sourceSrc = `package test
+
+type Request struct {
+	Host string
+	Path string
+}
+
+func Getenv(key string) string { return "" }
+
+func Cmd(s string) {}
+
+func run(s string) { Cmd(s) }
+
+func env() string { return Getenv("HOST") }
+
+func Env() { Cmd(env()) }
+
+func Field(r *Request) { Cmd(r.Host) }
+`
+
+const handlerSrc = `package test
+
+type Request struct {
+	Host string
+	Path string
+}
+
+func Cmd(s string) {}
+
+func run(s string) { Cmd(s) }
+
+func SrcHandler(r *Request) { defer run(r.Host) }
+`
+
+func TestSources(t *testing.T) {
+	// results of a call and reads of a field go through carriers, which pass through callees
+	assertFindings(t, analyze(t, sourceSrc, nil),
+		"source:(Request).Host#0->Cmd#0", "source:Getenv#0->Cmd#0")
+	// a field of a request which is a source parameter has been a finding from the parameter
+	assertFindings(t, analyze(t, handlerSrc, nil), "SrcHandler#0->Cmd#0")
+	assertFindings(t, analyze(t, handlerSrc, func(c *TaintConfig) { c.AccessPathDepth = 2 }), "SrcHandler#0->Cmd#0")
+}
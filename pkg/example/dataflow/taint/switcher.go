@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/cokeBeer/goot/pkg/dataflow/golang/switcher"
+	"github.com/cokeBeer/goot/pkg/example/dataflow/taint/rule"
 	"golang.org/x/tools/go/ssa"
 )
 
@@ -31,8 +32,46 @@ func (s *TaintSwitcher) CaseBinOp(inst *ssa.BinOp) {
 
 // CaseCall accepts a Call instruction
 func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
+	s.passCall(inst)
+}
+
+// CaseGo accepts a Go instruction
+func (s *TaintSwitcher) CaseGo(inst *ssa.Go) {
+	// a go call passes taint like an ordinary call, and its results are discarded
+	s.passCall(inst)
+}
+
+// CaseDefer accepts a Defer instruction
+func (s *TaintSwitcher) CaseDefer(inst *ssa.Defer) {
+	// a deferred call takes effect at RunDefers, see CaseRunDefers
+}
+
+// CaseRunDefers accepts a RunDefers instruction
+func (s *TaintSwitcher) CaseRunDefers(inst *ssa.RunDefers) {
+	// run deferred calls of the function in reverse order
+	// RunDefers is before every return of a function with defers
+	// we don't know which defers are executed, so run all of them
+	defers := make([]*ssa.Defer, 0)
+	for _, b := range s.taintAnalysis.Graph.Func.Blocks {
+		for _, instr := range b.Instrs {
+			if d, ok := instr.(*ssa.Defer); ok {
+				defers = append(defers, d)
+			}
+		}
+	}
+	for i := len(defers) - 1; i >= 0; i-- {
+		s.passCall(defers[i])
+	}
+}
+
+// passCall passes taint by a call, a go call or a deferred call
+func (s *TaintSwitcher) passCall(inst ssa.CallInstruction) {
+	// whichever callee is selected, mark results of a source and clear taint sanitized by the call at last,
+	// then pass taint written back to args to global variables they refer to
+	defer s.storeGlobalArgs(inst)
+	defer s.sanitizeCallTaint(inst)
+	defer s.sourceCallTaint(inst)
 	c := s.taintAnalysis.config
-	container := c.PassThroughContainer
 	init := s.taintAnalysis.config.InitMap
 	// try to use pointer analysis to select callee
 	callGraph := s.taintAnalysis.config.CallGraph
@@ -41,7 +80,7 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 		if node != nil {
 			for _, edge := range node.Out {
 				if edge.Site == inst {
-					if inst.Call.Method != nil {
+					if inst.Common().Method != nil {
 						// invoke
 						s.passMethodTaint(edge.Callee.Func, inst)
 					} else {
@@ -60,36 +99,36 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 		}
 	}
 	// try to use CHA to select callee
-	switch v := (inst.Call.Value).(type) {
+	switch v := (inst.Common().Value).(type) {
 	case *ssa.Field:
 		// caller can be a field from a struct
 		// we consider it as an interface
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// we consider is as a interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.FreeVar:
 		// caller can be a free var from closure
 		// we consider it as an interface
 		// e.g. bound$Write
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// we consider is as a interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.Lookup:
 		// caller can be a value from map
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			typ := v.X.Type().Underlying().(*types.Map).Elem()
 			if p, ok := typ.Underlying().(*types.Pointer); ok {
 				// anonymous function pointer
@@ -102,30 +141,30 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 			}
 		} else {
 			// if it is an interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.MakeInterface:
 		// caller can be a MakeInterface instruction
 		// we consider it as an interface
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// we consider is as a interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.TypeAssert:
 		// caller can be a TypeAssert instruction
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// we consider is as a interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.UnOp:
@@ -138,26 +177,26 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				// this case is special
 				// when use range over an interface pointer slice, it will hanppend
 				// e.g. golang.org/x/tools/go/ssa/sanity.go checkBlock
-				if inst.Call.Method != nil {
+				if inst.Common().Method != nil {
 					// we consider is as a interface
-					m := inst.Call.Method
+					m := inst.Common().Method
 					s.passInvokeTaint(m, inst)
 				}
 			default:
-				if inst.Call.Method == nil {
-					// if it is a function, its signature information is in inst.Call.Value
+				if inst.Common().Method == nil {
+					// if it is a function, its signature information is in inst.Common().Value
 					m := v.Type().Underlying().(*types.Signature)
 					s.passFuncParamTaint(m, inst)
 				} else {
 					// we consider is as a interface
-					m := inst.Call.Method
+					m := inst.Common().Method
 					s.passInvokeTaint(m, inst)
 				}
 			}
 		case *ssa.FreeVar:
 			// its inst.X can be a free var
-			if inst.Call.Method == nil {
-				// if it is a function, its signature information is in inst.Call.Value
+			if inst.Common().Method == nil {
+				// if it is a function, its signature information is in inst.Common().Value
 				typ := x.Type()
 				if p, ok := typ.Underlying().(*types.Pointer); ok {
 					// anonymous function pointer
@@ -170,7 +209,7 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				}
 			} else {
 				// if it is an interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		case *ssa.Global:
@@ -179,10 +218,10 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 			if ok {
 				// anonymous function that has been declared in source
 				s.passCallTaint(f, inst)
-			} else if inst.Call.Method != nil {
+			} else if inst.Common().Method != nil {
 				// a global anonymous interface created by function return
 				// e.g. go/types/universe.go universeAny = Universe.Lookup("any")
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			} else {
 				// anonymous function in assembly code
@@ -193,8 +232,8 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 			}
 		case *ssa.Alloc:
 			// its inst.X can be a local anonymous function or a local anonymous interface
-			if inst.Call.Method == nil {
-				// if it is a function, its signature information is in inst.Call.Value
+			if inst.Common().Method == nil {
+				// if it is a function, its signature information is in inst.Common().Value
 				// we try to find its *ssa.Function in referrers first
 				// e.g. runtime/mpagealloc_64bit.go sysGrow
 				ref := false
@@ -203,19 +242,11 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 						if f, ok := store.Val.(*ssa.Function); ok {
 							// if a function stored to inst.X
 							ref = ok
-							_, ok = (*container)[f.String()]
-							if !ok {
-								Run(f, c)
-							}
 							s.passCallTaint(f, inst)
 						} else if closure, ok := store.Val.(*ssa.MakeClosure); ok {
 							if f, ok := closure.Fn.(*ssa.Function); ok {
 								// if a closure stored to inst.X, retrive its Fn
 								ref = ok
-								_, ok = (*container)[f.String()]
-								if !ok {
-									Run(f, c)
-								}
 								s.passCallTaint(f, inst)
 							}
 						}
@@ -231,13 +262,13 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				}
 			} else {
 				// interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		case *ssa.FieldAddr:
 			// its inst.X can be a struct field, represents an anonymous function or an anonymous interface
 			// the struct can comes from reveiver or parameter
-			if inst.Call.Method == nil {
+			if inst.Common().Method == nil {
 				field := x.X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct).Field(x.Field)
 				typ := field.Type()
 				if p, ok := typ.Underlying().(*types.Pointer); ok {
@@ -251,12 +282,12 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				}
 			} else {
 				// interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		case *ssa.IndexAddr:
 			// its inst.X can be a slice cell, represents an anonymous function or an anonymous interface
-			if inst.Call.Method == nil {
+			if inst.Common().Method == nil {
 				if slice, ok := x.X.Type().Underlying().(*types.Slice); ok {
 					// if inst.X.X's underlying type is a slice
 					typ := slice.Elem()
@@ -292,14 +323,14 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				}
 			} else {
 				// interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		case *ssa.Extract:
 			// its inst.X can be an Extract instruction
 			// in this case, the function should hava more than one return value
-			if inst.Call.Method == nil {
-				// if it is a function, its signature information is in inst.Call.Value
+			if inst.Common().Method == nil {
+				// if it is a function, its signature information is in inst.Common().Value
 				typ := x.Type()
 				if p, ok := typ.Underlying().(*types.Pointer); ok {
 					// function pointer
@@ -312,80 +343,83 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 				}
 			} else {
 				// interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		case *ssa.Call:
-			if inst.Call.Method != nil {
+			if inst.Common().Method != nil {
 				// we consider is as a interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		default:
-			if inst.Call.Method == nil {
-				// if it is a function, its signature information is in inst.Call.Value
+			if inst.Common().Method == nil {
+				// if it is a function, its signature information is in inst.Common().Value
 				m := v.Type().Underlying().(*types.Signature)
 				s.passFuncParamTaint(m, inst)
 			} else {
 				// we consider is as a interface
-				m := inst.Call.Method
+				m := inst.Common().Method
 				s.passInvokeTaint(m, inst)
 			}
 		}
 	case *ssa.Phi:
 		// caller can be a Phi instruction
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			// we choose first edge here
 			m := v.Edges[0].Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.MakeClosure:
 		// caller can be a MakeClosure instruction
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if f, ok := v.Fn.(*ssa.Function); ok && inst.Common().Method == nil {
+			// the closure is called directly, so its bindings are known
+			s.passCallTaint(f, inst)
+		} else if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.Call:
 		// caller can be a Call instruction
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.Extract:
 		// caller can be a Extract instruction
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.Parameter:
 		// caller can be a parameter
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	case *ssa.Builtin:
@@ -410,7 +444,7 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 			"make",
 			"cap",
 			"ssa:wrapnilchk":
-			GetTaintWrapper(s.outMap, inst.Name())
+			GetTaintWrapper(s.outMap, callName(inst))
 		}
 	case *ssa.Function:
 		// caller can be a known function
@@ -418,13 +452,13 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 		f := v
 		s.passCallTaint(f, inst)
 	default:
-		if inst.Call.Method == nil {
-			// if it is a function, its signature information is in inst.Call.Value
+		if inst.Common().Method == nil {
+			// if it is a function, its signature information is in inst.Common().Value
 			m := v.Type().Underlying().(*types.Signature)
 			s.passFuncParamTaint(m, inst)
 		} else {
 			// we consider is as a interface
-			m := inst.Call.Method
+			m := inst.Common().Method
 			s.passInvokeTaint(m, inst)
 		}
 	}
@@ -433,19 +467,19 @@ func (s *TaintSwitcher) CaseCall(inst *ssa.Call) {
 // CaseChangeInterface accepts a ChangeInterface instruction
 func (s *TaintSwitcher) CaseChangeInterface(inst *ssa.ChangeInterface) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.passValue(inst.Name(), inst.X.Name())
 }
 
 // CaseChangeType accepts a ChangeType instruction
 func (s *TaintSwitcher) CaseChangeType(inst *ssa.ChangeType) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.passValue(inst.Name(), inst.X.Name())
 }
 
 // CaseConvert accepts a Convert instruction
 func (s *TaintSwitcher) CaseConvert(inst *ssa.Convert) {
 	// skip *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.passValue(inst.Name(), inst.X.Name())
 }
 
 // CaseExtract accepts a Extract instruction
@@ -453,48 +487,55 @@ func (s *TaintSwitcher) CaseExtract(inst *ssa.Extract) {
 	// mark the variables as "inst.Tuple.Name().i"
 	// e.g. t1.0, t3.2
 	mark := inst.Tuple.Name() + "." + strconv.Itoa(inst.Index)
-	PassTaint(s.outMap, inst.Name(), mark)
+	s.passValue(inst.Name(), mark)
 }
 
 // CaseField accepts a Field instruction
 func (s *TaintSwitcher) CaseField(inst *ssa.Field) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.readField(inst.Name(), inst.X.Name(), fieldName(inst.X.Type(), inst.Field))
+	s.sourceFieldTaint(inst.Name(), inst.X, inst.Field)
 }
 
 // CaseFieldAddr accepts a FieldAddr instruction
 func (s *TaintSwitcher) CaseFieldAddr(inst *ssa.FieldAddr) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.readField(inst.Name(), inst.X.Name(), fieldName(inst.X.Type(), inst.Field))
+	s.sourceFieldTaint(inst.Name(), inst.X, inst.Field)
 }
 
 // CaseIndex accepts an Index instruction
 func (s *TaintSwitcher) CaseIndex(inst *ssa.Index) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	// an element at a constant index has its own access path
+	s.readElement(inst.Name(), inst.X, inst.Index)
 }
 
 // CaseIndexAddr accepts an IndexAddr instruction
 func (s *TaintSwitcher) CaseIndexAddr(inst *ssa.IndexAddr) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	// an element at a constant index has its own access path
+	s.readElement(inst.Name(), inst.X, inst.Index)
 }
 
 // CaseLookup accepts a Lookup instruction
 func (s *TaintSwitcher) CaseLookup(inst *ssa.Lookup) {
 	// pass taint in index and map
+	name := inst.Name()
 	if inst.CommaOk {
 		// if needs an ok, mark two variables, and the first one inherits taint
-		PassTaint(s.outMap, inst.Name()+".0", inst.Index.Name(), inst.X.Name())
+		name = inst.Name() + ".0"
 		GetTaintWrapper(s.outMap, inst.Name()+".1")
-	} else {
-		PassTaint(s.outMap, inst.Name(), inst.Index.Name(), inst.X.Name())
 	}
+	PassTaint(s.outMap, name, inst.Index.Name())
+	// an element at a constant key has its own access path
+	s.readElement(name, inst.X, inst.Index)
 }
 
 // CaseMakeClosure accepts a MakeClosure instruction
 func (s *TaintSwitcher) CaseMakeClosure(inst *ssa.MakeClosure) {
 	GetTaintWrapper(s.outMap, inst.Name())
+	s.bindClosure(inst)
 }
 
 // CaseMakeChan accepts a MakeChan instruction
@@ -505,7 +546,7 @@ func (s *TaintSwitcher) CaseMakeChan(inst *ssa.MakeChan) {
 // CaseMakeInterface accepts a MakeInterface instruction
 func (s *TaintSwitcher) CaseMakeInterface(inst *ssa.MakeInterface) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	s.passValue(inst.Name(), inst.X.Name())
 }
 
 // CaseMakeMap accepts a MakeMap instruction
@@ -528,8 +569,11 @@ func (s *TaintSwitcher) CaseNext(inst *ssa.Next) {
 
 // CaseMapUpdate accepts a MapUpdate instruction
 func (s *TaintSwitcher) CaseMapUpdate(inst *ssa.MapUpdate) {
-	// pass taint in key and value
-	PassTaint(s.outMap, inst.Map.Name(), inst.Key.Name(), inst.Value.Name())
+	// pass taint in key and value, a value at a constant key has its own access path
+	PassTaint(s.outMap, inst.Map.Name(), inst.Key.Name())
+	s.writeElement(inst.Map, inst.Key, inst.Value.Name())
+	// the map may be a global variable
+	s.storeGlobal(inst.Map, inst.Map.Name(), inst)
 }
 
 // CasePhi accepts a Phi instruction
@@ -537,13 +581,14 @@ func (s *TaintSwitcher) CasePhi(inst *ssa.Phi) {
 	// Phi is the gather of instructions
 	// It may visit uninitialized register
 	for _, e := range inst.Edges {
-		PassTaint(s.outMap, inst.Name(), e.Name())
+		s.passValue(inst.Name(), e.Name())
 	}
 }
 
 // CaseRange accepts a Range instruction
 func (s *TaintSwitcher) CaseRange(inst *ssa.Range) {
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	// keys and values are at unknown indices
+	s.shiftElements(inst.Name(), inst.X.Name(), 0, false)
 }
 
 // CaseReturn accepts a Return instruction
@@ -552,32 +597,37 @@ func (s *TaintSwitcher) CaseReturn(inst *ssa.Return) {
 	if passThrough.HasRecv() {
 		// if the function has a receiver
 		recv := passThrough.RecvName()
-		for k := range *GetTaint(s.outMap, recv) {
-			// merge receiver's taint into passthrough
-			passThrough.Recv.AddTaint(k)
-		}
+		// merge receiver's taint into passthrough
+		passThrough.Recv.InheritTaint(s.outMap, recv)
+		s.returnFields(RecvPosition, 0, recv)
 	}
 	for i := 0; i < passThrough.ResultNum(); i++ {
 		result := inst.Results[i].Name()
 		// skip *ssa.Global, *ssa.FreeVar and *ssa.Const
-		for k := range *GetTaint(s.outMap, result) {
-			// merge other results' taint
-			passThrough.Results[i].AddTaint(k)
-		}
+		// merge other results' taint
+		passThrough.Results[i].InheritTaint(s.outMap, result)
+		s.returnFields(ResultPosition, i, result)
 	}
 	for i := 0; i < s.taintAnalysis.passThrough.ParamNum(); i++ {
 		arg := passThrough.ParamName(i)
 		// skip *ssa.Global, *ssa.FreeVar and *ssa.Const
-		for k := range *GetTaint(s.outMap, arg) {
-			// merge args' taint
-			passThrough.Params[i].AddTaint(k)
-		}
+		// merge args' taint
+		passThrough.Params[i].InheritTaint(s.outMap, arg)
+		s.returnFields(ParamPosition, i, arg)
+	}
+	for i := range passThrough.FreeVars {
+		// merge free variables' taint, they are written back to bindings of the closure
+		freeVar := passThrough.FreeVarName(i)
+		passThrough.FreeVars[i].InheritTaint(s.outMap, freeVar)
+		s.returnFields(FreeVarPosition, i, freeVar)
 	}
 }
 
 // CaseSend accepts a Send instruction
 func (s *TaintSwitcher) CaseSend(inst *ssa.Send) {
 	PassTaint(s.outMap, inst.Chan.Name(), inst.X.Name())
+	// the value may be received in another goroutine
+	s.sendTaint(inst.Chan, inst.X, inst)
 }
 
 // CaseSelect accepts a Select instruction
@@ -586,20 +636,32 @@ func (s *TaintSwitcher) CaseSelect(inst *ssa.Select) {
 	// e.g. t2.0, t2.1
 	GetTaintWrapper(s.outMap, inst.Name()+".0")
 	GetTaintWrapper(s.outMap, inst.Name()+".1")
-	for k := range inst.States {
-		GetTaintWrapper(s.outMap, inst.Name()+"."+strconv.Itoa(k+2))
+	// only receiving states have values, they are marked from t2.2
+	k := 2
+	for _, state := range inst.States {
+		if state.Dir == types.SendOnly {
+			PassTaint(s.outMap, state.Chan.Name(), state.Send.Name())
+			s.sendTaint(state.Chan, state.Send, inst)
+			continue
+		}
+		name := inst.Name() + "." + strconv.Itoa(k)
+		PassTaint(s.outMap, name, state.Chan.Name())
+		s.receiveTaint(name, state.Chan)
+		k++
 	}
 }
 
 // CaseSlice accepts a Slice instruction
 func (s *TaintSwitcher) CaseSlice(inst *ssa.Slice) {
-	PassTaint(s.outMap, inst.Name(), inst.X.Name())
+	// elements are moved by a constant low index
+	low, known := constIndex(inst.Low)
+	s.shiftElements(inst.Name(), inst.X.Name(), -low, known)
 }
 
 // CaseStore accepts a Store instruction
 func (s *TaintSwitcher) CaseStore(inst *ssa.Store) {
 	// Store needs to visit pointer
-	PassTaint(s.outMap, inst.Addr.Name(), inst.Val.Name())
+	s.passValue(inst.Addr.Name(), inst.Val.Name())
 	if _, ok := (inst.Addr).(*ssa.Global); ok {
 		// save global anonymous function to initMap
 		if f, ok := (inst.Val).(*ssa.Function); ok {
@@ -608,6 +670,8 @@ func (s *TaintSwitcher) CaseStore(inst *ssa.Store) {
 	}
 	// if inst.Addr points to struct or slice, update further
 	s.passPointTaint(inst.Addr)
+	// taint stored to a global variable is carried to other functions
+	s.storeGlobal(inst.Addr, inst.Val.Name(), inst)
 }
 
 // CaseTypeAssert accepts a TypeAssert instruction
@@ -615,10 +679,10 @@ func (s *TaintSwitcher) CaseTypeAssert(inst *ssa.TypeAssert) {
 	// we drop *ssa.Global, *ssa.FreeVar and *ssa.Const
 	if inst.CommaOk {
 		// if needs an ok, mark two variables, and the first one inherits taint
-		PassTaint(s.outMap, inst.Name()+".0", inst.X.Name())
+		s.passValue(inst.Name()+".0", inst.X.Name())
 		GetTaintWrapper(s.outMap, inst.Name()+".1")
 	} else {
-		PassTaint(s.outMap, inst.Name(), inst.X.Name())
+		s.passValue(inst.Name(), inst.X.Name())
 	}
 }
 
@@ -628,13 +692,19 @@ func (s *TaintSwitcher) CaseUnOp(inst *ssa.UnOp) {
 		// if needs an ok, mark two variables, and the first one inherits taint
 		PassTaint(s.outMap, inst.Name()+".0", inst.X.Name())
 		GetTaintWrapper(s.outMap, inst.Name()+".1")
+		s.receiveTaint(inst.Name()+".0", inst.X)
 	} else {
-		PassTaint(s.outMap, inst.Name(), inst.X.Name())
+		s.passValue(inst.Name(), inst.X.Name())
+		if inst.Op == token.ARROW {
+			s.receiveTaint(inst.Name(), inst.X)
+		} else if inst.Op == token.MUL {
+			s.loadGlobal(inst.Name(), inst.X)
+		}
 	}
 }
 
 // passCallTaint passes taint by *ssa.Function and a call
-func (s *TaintSwitcher) passCallTaint(f *ssa.Function, inst *ssa.Call) {
+func (s *TaintSwitcher) passCallTaint(f *ssa.Function, inst ssa.CallInstruction) {
 	if !s.taintAnalysis.config.PassThroughOnly {
 		s.collectCallEdges(f, inst)
 	}
@@ -642,74 +712,103 @@ func (s *TaintSwitcher) passCallTaint(f *ssa.Function, inst *ssa.Call) {
 }
 
 // passStaticCallTaint passes taint by a known *ssa.Function and a call
-func (s *TaintSwitcher) passStaticCallTaint(f *ssa.Function, inst *ssa.Call) {
-	container := s.taintAnalysis.config.PassThroughContainer
-	c := s.taintAnalysis.config
-	_, ok := (*container)[f.String()]
+func (s *TaintSwitcher) passStaticCallTaint(f *ssa.Function, inst ssa.CallInstruction) {
+	args := s.callArgs(f, inst)
+	passThroughCache, ok := s.lookupPassThrough(f, args)
 	if !ok {
-		if needNull(f, c) {
-			// function is loaded from C file and has no body
-			if m, ok := f.Object().(*types.Func); ok {
-				s.passNullTaint(m, inst)
-			} else {
-				// function pointer used in recursive
-				// e.g. golang.org/x/tools/go/analysis/validate.go Validate$1 visit
-				// to inhibit infinite recursive, use passAnonymousTaint instead of passFuncParamTaint
-				s.passAnonymousTaint(f.Signature, inst)
-			}
-			return
+		// function has no summary because it is not scheduled before this call
+		// e.g. function is loaded from C file and has no body
+		if m, ok := f.Object().(*types.Func); ok {
+			s.passNullTaint(m, inst)
+		} else {
+			// anonymous function has no object
+			s.passAnonymousTaint(f.Signature, inst)
 		}
-		// if we can saved it, load it now
-		Run(f, c)
+		return
+	}
+	// the callee may send args to carriers, like globals and channels
+	s.passSends(f, passThroughCache, args, inst)
+	s.passStaticPassThrough(passThroughCache, args, inst)
+	if closureOf(f, inst) != nil {
+		// bindings of free variables are only known for a known closure
+		s.passFreeVarTaint(passThroughCache, args)
 	}
+}
 
-	passThroughCache := (*container)[f.String()]
+// passStaticPassThrough passes taint by passthrough of a known callee and a call
+// args are values passed to the callee's parameters, followed by bindings of free variables if the callee is a known closure
+func (s *TaintSwitcher) passStaticPassThrough(passThroughCache *PassThroughCache, args []ssa.Value, inst ssa.CallInstruction) {
 	var newRecvTaint *TaintWrapper
+	newRecvFields := make(map[string]*TaintWrapper)
 	newResultTaints := make([]*TaintWrapper, 0)
+	newResultFields := make([]map[string]*TaintWrapper, 0)
 	newParamTaints := make([]*TaintWrapper, 0)
+	newParamFields := make([]map[string]*TaintWrapper, 0)
 	if passThroughCache.HasRecv() {
 		newTaint := NewTaintWrapper()
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range passThroughCache.Recv {
-			newTaint.InheritTaint(s.outMap, inst.Call.Args[p].Name())
+			if p >= len(args) {
+				// free variables of a closure called as a value are unknown
+				continue
+			}
+			s.inheritPassThrough(newTaint, newRecvFields, passThroughCache, RecvPosition, 0, p, args[p].Name())
 		}
+		inheritCarriers(newTaint, passThroughCache, RecvPosition, 0)
 		newRecvTaint = newTaint
 	}
-	for _, result := range passThroughCache.Results {
+	for i, result := range passThroughCache.Results {
 		newTaint := NewTaintWrapper()
+		newFields := make(map[string]*TaintWrapper)
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range result {
-			newTaint.InheritTaint(s.outMap, inst.Call.Args[p].Name())
+			if p >= len(args) {
+				// free variables of a closure called as a value are unknown
+				continue
+			}
+			s.inheritPassThrough(newTaint, newFields, passThroughCache, ResultPosition, i, p, args[p].Name())
 		}
+		inheritCarriers(newTaint, passThroughCache, ResultPosition, i)
 		newResultTaints = append(newResultTaints, newTaint)
+		newResultFields = append(newResultFields, newFields)
 	}
-	for _, param := range passThroughCache.Params {
+	for i, param := range passThroughCache.Params {
 		newTaint := NewTaintWrapper()
+		newFields := make(map[string]*TaintWrapper)
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range param {
-			newTaint.InheritTaint(s.outMap, inst.Call.Args[p].Name())
+			if p >= len(args) {
+				// free variables of a closure called as a value are unknown
+				continue
+			}
+			s.inheritPassThrough(newTaint, newFields, passThroughCache, ParamPosition, i, p, args[p].Name())
 		}
+		inheritCarriers(newTaint, passThroughCache, ParamPosition, i)
 		newParamTaints = append(newParamTaints, newTaint)
+		newParamFields = append(newParamFields, newFields)
 	}
 	if passThroughCache.HasRecv() {
 		// update receiver's taint
 		// the receiver may be a pointer, so update further by the pointer
-		SetTaintWrapper(s.outMap, inst.Call.Args[0].Name(), newRecvTaint)
-		if op, ok := (inst.Call.Args[0]).(*ssa.UnOp); ok {
-			PassTaint(s.outMap, op.X.Name(), op.Name())
+		SetTaintWrapper(s.outMap, args[0].Name(), newRecvTaint)
+		s.setFields(args[0].Name(), newRecvFields)
+		if op, ok := (args[0]).(*ssa.UnOp); ok {
+			s.passValue(op.X.Name(), op.Name())
 			s.passPointTaint(op.X)
 		} else {
-			s.passPointTaint(inst.Call.Args[0])
+			s.passPointTaint(args[0])
 		}
 	}
 	for i := 0; i < passThroughCache.ResultNum(); i++ {
 		if passThroughCache.ResultNum() == 1 {
 			// if the function has one result
-			SetTaintWrapper(s.outMap, inst.Name(), newResultTaints[i])
+			SetTaintWrapper(s.outMap, callName(inst), newResultTaints[i])
+			s.setFields(callName(inst), newResultFields[i])
 		} else {
-			// else mark the variables as "inst.Name().X"
+			// else mark the variables as "callName(inst).X"
 			// e.g. t0.1, t0.2
-			SetTaintWrapper(s.outMap, inst.Name()+"."+strconv.Itoa(i), newResultTaints[i])
+			SetTaintWrapper(s.outMap, callName(inst)+"."+strconv.Itoa(i), newResultTaints[i])
+			s.setFields(callName(inst)+"."+strconv.Itoa(i), newResultFields[i])
 		}
 	}
 	for i := 0; i < passThroughCache.ParamNum(); i++ {
@@ -720,8 +819,9 @@ func (s *TaintSwitcher) passStaticCallTaint(f *ssa.Function, inst *ssa.Call) {
 			recv = 0
 		}
 		// update args' taint, use passPointTaint to pass back
-		SetTaintWrapper(s.outMap, inst.Call.Args[recv+i].Name(), newParamTaints[i])
-		s.passPointTaint(inst.Call.Args[recv+i])
+		SetTaintWrapper(s.outMap, args[recv+i].Name(), newParamTaints[i])
+		s.setFields(args[recv+i].Name(), newParamFields[i])
+		s.passPointTaint(args[recv+i])
 	}
 }
 
@@ -732,45 +832,45 @@ func (s *TaintSwitcher) passPointTaint(pointer ssa.Value) {
 		for _, _inst := range *addr.Referrers() {
 			switch inst := _inst.(type) {
 			case *ssa.Store:
-				PassTaint(s.outMap, inst.Val.Name(), addr.Name())
+				s.passValue(inst.Val.Name(), addr.Name())
 				s.passBackCallTaint(inst.Val)
 			}
 		}
 	case *ssa.Convert:
 		// if addr is a *ssa.Convert, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.TypeAssert:
 		// if addr is a *ssa.TypeAssert, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.ChangeType:
 		// if addr is a *ssa.ChangeType, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.ChangeInterface:
 		// if addr is a *ssa.ChangeInterface, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.MakeInterface:
 		// if addr is a *ssa.MakeInterface, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.UnOp:
 		// if addr is a *ssa.UnOp, try use its addr.X to update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.FieldAddr:
-		// if addr is still a *ssa.FieldAddr, update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		// if addr is still a *ssa.FieldAddr, update the field further
+		s.writeField(addr.X.Name(), fieldName(addr.X.Type(), addr.Field), addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.IndexAddr:
-		// if addr is a *ssa.IndexAddr, update further
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		// if addr is a *ssa.IndexAddr, update the element further
+		s.writeElement(addr.X, addr.Index, addr.Name())
 		s.passPointTaint(addr.X)
 	case *ssa.Slice:
 		// if addr is a *ssa.Slice, update underlying array
-		PassTaint(s.outMap, addr.X.Name(), addr.Name())
+		s.passValue(addr.X.Name(), addr.Name())
 	}
 }
 
@@ -786,134 +886,181 @@ func (s *TaintSwitcher) passBackCallTaint(_call ssa.Value) {
 }
 
 // passAppendTaint passes taint by append
-func (s *TaintSwitcher) passAppendTaint(inst *ssa.Call) {
-	newTaint := NewTaintWrapper()
-	n := len(inst.Call.Args)
-	for i := 0; i < n; i++ {
-		// collect taint in slices
-		// need *ssa.UnOp，may be more other types
-		// e.g. path/path.go Join
-		// buf = append(buf, e...)
-		newTaint.InheritTaint(s.outMap, inst.Call.Args[i].Name())
+func (s *TaintSwitcher) passAppendTaint(inst ssa.CallInstruction) {
+	n := len(inst.Common().Args)
+	if s.depth() != 0 {
+		// appended elements follow elements of the slice, they are at unknown indices if its length is unknown
+		// e.g. append([]string{"git", "log"}, ref) puts ref at [2]
+		args := inst.Common().Args
+		SetTaintWrapper(s.outMap, callName(inst), NewTaintWrapper())
+		s.passValue(callName(inst), args[0].Name())
+		offset, known := constLen(args[0])
+		s.shiftElements(callName(inst), args[1].Name(), offset, known)
+	} else {
+		newTaint := NewTaintWrapper()
+		for i := 0; i < n; i++ {
+			// collect taint in slices
+			// need *ssa.UnOp，may be more other types
+			// e.g. path/path.go Join
+			// buf = append(buf, e...)
+			newTaint.InheritTaint(s.outMap, inst.Common().Args[i].Name())
+		}
+		SetTaintWrapper(s.outMap, callName(inst), newTaint)
 	}
-	SetTaintWrapper(s.outMap, inst.Name(), newTaint)
 	for i := 0; i < n; i++ {
 		// pass taint to every slice
-		PassTaint(s.outMap, inst.Call.Args[i].Name(), inst.Name())
+		s.passValue(inst.Common().Args[i].Name(), callName(inst))
 	}
 }
 
 // passInvokeTaint passes taint by *types.Func
 // actually, only interfaces use this
-func (s *TaintSwitcher) passInvokeTaint(f *types.Func, inst *ssa.Call) {
+func (s *TaintSwitcher) passInvokeTaint(f *types.Func, inst ssa.CallInstruction) {
 	if !s.taintAnalysis.config.PassThroughOnly {
 		s.collectMethodEdges(f, inst)
 	}
+	if m := s.boundMethod(inst.Common().Value, f); m != nil {
+		// the interface is a parameter bound to a concrete type by the context
+		s.passMethodTaint(m, inst)
+		return
+	}
 	interfaceHierarchy := s.taintAnalysis.config.InterfaceHierarchy
-	tiface := inst.Call.Value.Type().Underlying().(*types.Interface)
+	tiface := inst.Common().Value.Type().Underlying().(*types.Interface)
 	methods := interfaceHierarchy.LookupMethods(tiface, f)
-	if len(methods) != 0 {
-		s.passMethodTaint(methods[0], inst)
+	// the first arg is inst.Common().Value
+	args := append([]ssa.Value{inst.Common().Value}, inst.Common().Args...)
+	caches := make([]*PassThroughCache, 0)
+	for _, m := range methods {
+		if !s.taintAnalysis.config.PassThroughOnly {
+			s.collectArgEdges(m, args, inst)
+		}
+		if passThroughCache, ok := s.lookupPassThrough(m, args); ok {
+			s.passSends(m, passThroughCache, args, inst)
+			caches = append(caches, passThroughCache)
+		}
+	}
+	if len(caches) != 0 {
+		// any of the implementations may be called, so join their passthrough
+		s.passMethodPassThrough(JoinPassThrough(caches), inst)
 	} else {
 		s.passNullTaint(f, inst)
 	}
 }
 
 // passMethodTaint passes taint by *ssa.Function and an invoke
-func (s *TaintSwitcher) passMethodTaint(f *ssa.Function, inst *ssa.Call) {
-	container := s.taintAnalysis.config.PassThroughContainer
-	c := s.taintAnalysis.config
-	_, ok := (*container)[f.String()]
+func (s *TaintSwitcher) passMethodTaint(f *ssa.Function, inst ssa.CallInstruction) {
+	// the first arg is inst.Common().Value
+	args := append([]ssa.Value{inst.Common().Value}, inst.Common().Args...)
+	passThroughCache, ok := s.lookupPassThrough(f, args)
 	if !ok {
-		if needNull(f, c) {
-			// function is loaded from C file and has no body
-			m, ok := f.Object().(*types.Func)
-			if ok {
-				s.passNullTaint(m, inst)
-			}
-			return
+		// function has no summary because it is not scheduled before this call
+		// e.g. function is loaded from C file and has no body
+		if m, ok := f.Object().(*types.Func); ok {
+			s.passNullTaint(m, inst)
+		} else {
+			s.passAnonymousTaint(f.Signature, inst)
 		}
-		// if we can saved it, load it now
-		Run(f, c)
+		return
 	}
+	// the callee may send args to carriers, like globals and channels
+	s.passSends(f, passThroughCache, args, inst)
+	s.passMethodPassThrough(passThroughCache, inst)
+}
 
-	passThroughCache := (*container)[f.String()]
+// passMethodPassThrough passes taint by passthrough of a method and an invoke
+func (s *TaintSwitcher) passMethodPassThrough(passThroughCache *PassThroughCache, inst ssa.CallInstruction) {
 	var newRecvTaint *TaintWrapper
+	newRecvFields := make(map[string]*TaintWrapper)
 	newResultTaints := make([]*TaintWrapper, 0)
+	newResultFields := make([]map[string]*TaintWrapper, 0)
 	newParamTaints := make([]*TaintWrapper, 0)
+	newParamFields := make([]map[string]*TaintWrapper, 0)
 	if passThroughCache.HasRecv() {
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range passThroughCache.Recv {
 			newTaint := NewTaintWrapper()
 			if p == 0 {
-				// the first arg is inst.Call.Value
-				newTaint.InheritTaint(s.outMap, inst.Call.Value.Name())
+				// the first arg is inst.Common().Value
+				s.inheritPassThrough(newTaint, newRecvFields, passThroughCache, RecvPosition, 0, p, inst.Common().Value.Name())
 			} else {
-				// other args are in inst.Call.Args
-				newTaint.InheritTaint(s.outMap, inst.Call.Args[p-1].Name())
+				// other args are in inst.Common().Args
+				s.inheritPassThrough(newTaint, newRecvFields, passThroughCache, RecvPosition, 0, p, inst.Common().Args[p-1].Name())
 			}
 			newRecvTaint = newTaint
 		}
 	}
-	for _, result := range passThroughCache.Results {
+	if newRecvTaint != nil {
+		inheritCarriers(newRecvTaint, passThroughCache, RecvPosition, 0)
+	}
+	for i, result := range passThroughCache.Results {
 		newTaint := NewTaintWrapper()
+		newFields := make(map[string]*TaintWrapper)
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range result {
 			if p == 0 {
-				// the first arg is inst.Call.Value
-				newTaint.InheritTaint(s.outMap, inst.Call.Value.Name())
+				// the first arg is inst.Common().Value
+				s.inheritPassThrough(newTaint, newFields, passThroughCache, ResultPosition, i, p, inst.Common().Value.Name())
 			} else {
-				// other args are in inst.Call.Args
-				newTaint.InheritTaint(s.outMap, inst.Call.Args[p-1].Name())
+				// other args are in inst.Common().Args
+				s.inheritPassThrough(newTaint, newFields, passThroughCache, ResultPosition, i, p, inst.Common().Args[p-1].Name())
 			}
 		}
+		inheritCarriers(newTaint, passThroughCache, ResultPosition, i)
 		newResultTaints = append(newResultTaints, newTaint)
+		newResultFields = append(newResultFields, newFields)
 	}
-	for _, param := range passThroughCache.Params {
+	for i, param := range passThroughCache.Params {
 		newTaint := NewTaintWrapper()
+		newFields := make(map[string]*TaintWrapper)
 		// for every parameter index in passthrough, collect arg's taint
 		for _, p := range param {
 			if p == 0 {
-				// the first arg is inst.Call.Value
-				newTaint.InheritTaint(s.outMap, inst.Call.Value.Name())
+				// the first arg is inst.Common().Value
+				s.inheritPassThrough(newTaint, newFields, passThroughCache, ParamPosition, i, p, inst.Common().Value.Name())
 			} else {
-				// other args are in inst.Call.Args
-				newTaint.InheritTaint(s.outMap, inst.Call.Args[p-1].Name())
+				// other args are in inst.Common().Args
+				s.inheritPassThrough(newTaint, newFields, passThroughCache, ParamPosition, i, p, inst.Common().Args[p-1].Name())
 			}
 		}
+		inheritCarriers(newTaint, passThroughCache, ParamPosition, i)
 		newParamTaints = append(newParamTaints, newTaint)
+		newParamFields = append(newParamFields, newFields)
 	}
 	if passThroughCache.HasRecv() {
 		// update receiver's taint
 		// the receiver may be a pointer, so update further by the pointer
-		SetTaintWrapper(s.outMap, inst.Call.Value.Name(), newRecvTaint)
-		if op, ok := (inst.Call.Value).(*ssa.UnOp); ok {
-			PassTaint(s.outMap, op.X.Name(), op.Name())
+		SetTaintWrapper(s.outMap, inst.Common().Value.Name(), newRecvTaint)
+		s.setFields(inst.Common().Value.Name(), newRecvFields)
+		if op, ok := (inst.Common().Value).(*ssa.UnOp); ok {
+			s.passValue(op.X.Name(), op.Name())
 			s.passPointTaint(op.X)
 		} else {
-			s.passPointTaint(inst.Call.Value)
+			s.passPointTaint(inst.Common().Value)
 		}
 	}
 	for i := 0; i < passThroughCache.ResultNum(); i++ {
 		if passThroughCache.ResultNum() == 1 {
 			// if the function has one result
-			SetTaintWrapper(s.outMap, inst.Name(), newResultTaints[i])
+			SetTaintWrapper(s.outMap, callName(inst), newResultTaints[i])
+			s.setFields(callName(inst), newResultFields[i])
 		} else {
-			// else mark the variables as "inst.Name().X"
+			// else mark the variables as "callName(inst).X"
 			// e.g. t0.1, t0.2
-			SetTaintWrapper(s.outMap, inst.Name()+"."+strconv.Itoa(i), newResultTaints[i])
+			SetTaintWrapper(s.outMap, callName(inst)+"."+strconv.Itoa(i), newResultTaints[i])
+			s.setFields(callName(inst)+"."+strconv.Itoa(i), newResultFields[i])
 		}
 	}
 	for i := 0; i < passThroughCache.ParamNum(); i++ {
 		// update args' taint
-		SetTaintWrapper(s.outMap, inst.Call.Args[i].Name(), newParamTaints[i])
+		SetTaintWrapper(s.outMap, inst.Common().Args[i].Name(), newParamTaints[i])
+		s.setFields(inst.Common().Args[i].Name(), newParamFields[i])
 	}
 }
 
 // passNullTaint passes taint when we can't know a declared function's body or have to inhibit recursive
 // actually no taint will be passed
 // note that this may lose some taint but help analysis keep working
-func (s *TaintSwitcher) passNullTaint(f *types.Func, inst *ssa.Call) {
+func (s *TaintSwitcher) passNullTaint(f *types.Func, inst ssa.CallInstruction) {
 	signature, ok := f.Type().(*types.Signature)
 	if ok {
 		recv := signature.Recv() != nil
@@ -925,9 +1072,9 @@ func (s *TaintSwitcher) passNullTaint(f *types.Func, inst *ssa.Call) {
 		}
 		for i := 0; i < result; i++ {
 			if result == 1 {
-				GetTaintWrapper(s.outMap, inst.Name())
+				GetTaintWrapper(s.outMap, callName(inst))
 			} else {
-				GetTaintWrapper(s.outMap, inst.Name()+"."+strconv.Itoa(i))
+				GetTaintWrapper(s.outMap, callName(inst)+"."+strconv.Itoa(i))
 			}
 		}
 		for i := 0; i < param; i++ {
@@ -938,14 +1085,27 @@ func (s *TaintSwitcher) passNullTaint(f *types.Func, inst *ssa.Call) {
 
 // passFuncParamTaint passes taint by *types.Signature
 // actually, only functions without body use this
-func (s *TaintSwitcher) passFuncParamTaint(signature *types.Signature, inst *ssa.Call) {
+func (s *TaintSwitcher) passFuncParamTaint(signature *types.Signature, inst ssa.CallInstruction) {
+	if f := s.boundFunc(inst.Common().Value); f != nil {
+		// the function is a parameter bound to a function by the context
+		s.passCallTaint(f, inst)
+		return
+	}
 	if !s.taintAnalysis.config.PassThroughOnly {
 		s.collectSignatureEdges(signature, inst)
 	}
 	interfaceHierarchy := s.taintAnalysis.config.InterfaceHierarchy
 	funcs := interfaceHierarchy.LookupFuncs(signature)
-	if len(funcs) != 0 {
-		s.passStaticCallTaint(funcs[0], inst)
+	caches := make([]*PassThroughCache, 0)
+	for _, f := range funcs {
+		if passThroughCache, ok := s.lookupPassThrough(f, inst.Common().Args); ok {
+			s.passSends(f, passThroughCache, inst.Common().Args, inst)
+			caches = append(caches, passThroughCache)
+		}
+	}
+	if len(caches) != 0 {
+		// any of the functions may be called, so join their passthrough
+		s.passStaticPassThrough(JoinPassThrough(caches), inst.Common().Args, inst)
 		return
 	}
 	s.passAnonymousTaint(signature, inst)
@@ -953,7 +1113,7 @@ func (s *TaintSwitcher) passFuncParamTaint(signature *types.Signature, inst *ssa
 
 // passAnonymousTaint called by passFuncParamTaint
 // it does not save passthrough to passthroughContainer
-func (s *TaintSwitcher) passAnonymousTaint(signature *types.Signature, inst *ssa.Call) {
+func (s *TaintSwitcher) passAnonymousTaint(signature *types.Signature, inst ssa.CallInstruction) {
 	passThrough := make([][]int, 0)
 	n := signature.Results().Len()
 	for i := 0; i < n; i++ {
@@ -961,155 +1121,214 @@ func (s *TaintSwitcher) passAnonymousTaint(signature *types.Signature, inst *ssa
 	}
 	n = len(passThrough)
 	if n == 1 {
-		GetTaintWrapper(s.outMap, inst.Name())
+		GetTaintWrapper(s.outMap, callName(inst))
 	} else {
 		for i := 0; i < n; i++ {
 			if n != 1 {
-				GetTaintWrapper(s.outMap, inst.Name()+"."+strconv.Itoa(i))
+				GetTaintWrapper(s.outMap, callName(inst)+"."+strconv.Itoa(i))
 			}
 		}
 	}
 }
 
 // passCopyTaint pass taint by copy
-func (s *TaintSwitcher) passCopyTaint(inst *ssa.Call) {
-	PassTaint(s.outMap, inst.Call.Args[0].Name(), inst.Call.Args[1].Name())
-	GetTaintWrapper(s.outMap, inst.Name())
+func (s *TaintSwitcher) passCopyTaint(inst ssa.CallInstruction) {
+	PassTaint(s.outMap, inst.Common().Args[0].Name(), inst.Common().Args[1].Name())
+	GetTaintWrapper(s.outMap, callName(inst))
+}
+
+// sanitizeCallTaint sanitizes taint of results and args of a call which are sanitized by the ruler
+// taint is cleared if the sanitizer removes all labels, else labels are removed from it
+func (s *TaintSwitcher) sanitizeCallTaint(inst ssa.CallInstruction) {
+	ruler := s.taintAnalysis.config.Ruler
+	node := calleeNode(inst)
+	if node == nil {
+		return
+	}
+	node.Index = rule.ResultIndex
+	if ruler.IsSanitizer(node) {
+		labels := rule.SanitizerLabelsOf(ruler, node)
+		for _, name := range resultNames(inst) {
+			s.sanitizeTaint(name, labels)
+		}
+	}
+	args := inst.Common().Args
+	if inst.Common().IsInvoke() {
+		// the receiver of an invoke is inst.Common().Value
+		args = append([]ssa.Value{inst.Common().Value}, args...)
+	}
+	for i, arg := range args {
+		node.Index = i
+		if ruler.IsSanitizer(node) {
+			s.sanitizeTaint(arg.Name(), rule.SanitizerLabelsOf(ruler, node))
+		}
+	}
 }
 
-func (s *TaintSwitcher) collectCallEdges(f *ssa.Function, inst *ssa.Call) {
+// calleeNode returns a node of the callee of a call to ask the ruler, or nil if the callee is unknown
+// the callee is known by a static function or an interface method
+func calleeNode(inst ssa.CallInstruction) *Node {
+	if f := inst.Common().StaticCallee(); f != nil {
+		return &Node{Function: f, Canonical: f.String()}
+	} else if inst.Common().Method != nil {
+		return &Node{Canonical: inst.Common().Method.FullName()}
+	}
+	return nil
+}
+
+// resultNames returns keys of results of a call, a call with several results has keys like t1.0, t1.1
+func resultNames(inst ssa.CallInstruction) []string {
+	n := inst.Common().Signature().Results().Len()
+	if n == 1 {
+		return []string{callName(inst)}
+	}
+	names := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		names = append(names, callName(inst)+"."+strconv.Itoa(i))
+	}
+	return names
+}
+
+// sanitizeTaint replaces the wrapper with a key by a sanitized one
+// the old wrapper is not changed because it may be shared by other flows
+func (s *TaintSwitcher) sanitizeTaint(name string, labels []string) {
+	newTaint := NewTaintWrapper()
+	if len(labels) != 0 {
+		newTaint.InheritTaint(s.outMap, name, labels...)
+	}
+	SetTaintWrapper(s.outMap, name, newTaint)
+	if s.depth() != 0 {
+		// fields of the value are sanitized too
+		for _, sub := range subPaths(s.outMap, name, "") {
+			s.sanitizeTaint(fieldKey(name, sub), labels)
+		}
+	}
+}
+
+// collectCallEdges collects edges from args of a call to parameters of a known callee
+func (s *TaintSwitcher) collectCallEdges(f *ssa.Function, inst ssa.CallInstruction) {
+	s.collectArgEdges(f, inst.Common().Args, inst)
+}
+
+// collectArgEdges collects edges from origins of taint carried by args to parameters of a callee
+// args are values passed to the callee's parameters, like inst.Common().Value and inst.Common().Args of an invoke,
+// or bindings of free variables of a closure, a nil arg is skipped
+func (s *TaintSwitcher) collectArgEdges(f *ssa.Function, args []ssa.Value, inst ssa.Instruction) {
 	taintGraph := s.taintAnalysis.config.TaintGraph
 	if s.taintAnalysis.Graph.Func.Name() == "init" {
 		return
 	}
-	for i, arg := range inst.Call.Args {
-		for name := range *GetTaint(s.outMap, arg.Name()) {
-			for k, v := range s.taintAnalysis.Graph.Func.Params {
-				if v.Name() == name {
-					edge := Edge{From: s.taintAnalysis.Graph.Func.String(), FromIndex: k, To: f.String(), ToIndex: i}
-					key := s.taintAnalysis.Graph.Func.String() + "#" + strconv.Itoa(k)
-					key2 := f.String() + "#" + strconv.Itoa(i)
-					node := (*taintGraph.Nodes)[key]
-					node2 := (*taintGraph.Nodes)[key2]
-					if node.IsIntra {
-						if _, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
-							continue
-						} else {
-							(*taintGraph.Edges)[key+"#"+key2] = &edge
-						}
-						node.Out = append(node.Out, &edge)
-						node2.In = append(node2.In, &edge)
-						passProperty(node2, &edge)
+	for i, arg := range args {
+		if arg == nil {
+			continue
+		}
+		carried := s.carriedTaint(arg.Name())
+		for name := range *carried.innerTaint {
+			if node, key, ok := s.taintOrigin(name); ok {
+				edge := Edge{From: node.Canonical, FromIndex: node.Index, To: f.String(), ToIndex: i, Labels: s.edgeLabels(carried, name, node), Position: s.position(inst)}
+				key2 := f.String() + "#" + strconv.Itoa(i)
+				node2 := (*taintGraph.Nodes)[key2]
+				if node.IsIntra {
+					if old, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
+						mergeEdge(old, &edge, node2)
+						continue
+					} else {
+						(*taintGraph.Edges)[key+"#"+key2] = &edge
 					}
+					node.Out = append(node.Out, &edge)
+					node2.In = append(node2.In, &edge)
+					passProperty(node2, &edge)
 				}
 			}
 		}
 	}
 }
 
-// collectMethodsEdges records node only use type information
-func (s *TaintSwitcher) collectMethodEdges(f *types.Func, inst *ssa.Call) {
-	signature, ok := f.Type().(*types.Signature)
+// collectMethodEdges collects edges from the receiver and args of an invoke to a method only known by its type
+// the receiver is at index 0 and args follow it
+func (s *TaintSwitcher) collectMethodEdges(f *types.Func, inst ssa.CallInstruction) {
+	s.collectTypeEdges(inst.Common().Value, f.FullName(), 0, true, inst)
+	for i, arg := range inst.Common().Args {
+		s.collectTypeEdges(arg, f.FullName(), i+1, true, inst)
+	}
+}
+
+// collectSignatureEdges collects edges from args of a call to a function only known by its signature
+func (s *TaintSwitcher) collectSignatureEdges(signature *types.Signature, inst ssa.CallInstruction) {
+	for i, arg := range inst.Common().Args {
+		s.collectTypeEdges(arg, signature.String(), i, false, inst)
+	}
+}
+
+// collectTypeEdges collects edges from origins of taint carried by an arg to a callee only known by its type
+// the node of the callee at the index is created by the first edge to it
+func (s *TaintSwitcher) collectTypeEdges(arg ssa.Value, to string, index int, isMethod bool, inst ssa.CallInstruction) {
 	ruler := s.taintAnalysis.config.Ruler
 	taintGraph := s.taintAnalysis.config.TaintGraph
-	if ok {
-		for name := range *GetTaint(s.outMap, inst.Call.Value.Name()) {
-			// contruct taint edge from receiver to arg
-			for k, v := range s.taintAnalysis.Graph.Func.Params {
-				if v.Name() == name {
-					edge := Edge{From: s.taintAnalysis.Graph.Func.String(), FromIndex: k, To: f.String(), ToIndex: 0}
-					key := s.taintAnalysis.Graph.Func.String() + "#" + strconv.Itoa(k)
-					key2 := f.String() + "#" + strconv.Itoa(0)
-					node := (*taintGraph.Nodes)[key]
-					if node.IsIntra {
-						node.Out = append(node.Out, &edge)
-						if _, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
-							continue
-						} else {
-							(*taintGraph.Edges)[key+"#"+key2] = &edge
-						}
-						if node2, ok := (*taintGraph.Nodes)[key2]; ok {
-							node2.In = append(node2.In, &edge)
-							passProperty(node2, &edge)
-						} else {
-							node2 := &Node{Canonical: signature.String(), Index: 0, Out: make([]*Edge, 0), In: make([]*Edge, 0), IsSignature: false, IsMethod: true, IsStatic: false}
-							decidePropertry(node2, ruler)
-							node2.In = append(node2.In, &edge)
-							(*taintGraph.Nodes)[f.String()] = node2
-							passProperty(node2, &edge)
-						}
-					}
-				}
-			}
+	key2 := to + "#" + strconv.Itoa(index)
+	carried := s.carriedTaint(arg.Name())
+	for name := range *carried.innerTaint {
+		node, key, ok := s.taintOrigin(name)
+		if !ok || !node.IsIntra {
+			continue
 		}
-		n := signature.Params().Len()
-		for i := 0; i < n; i++ {
-			// contruct taint edge from param to arg
-			for name := range *GetTaint(s.outMap, inst.Call.Args[i].Name()) {
-				for k, v := range s.taintAnalysis.Graph.Func.Params {
-					if v.Name() == name {
-						edge := Edge{From: s.taintAnalysis.Graph.Func.String(), FromIndex: k, To: f.String(), ToIndex: i + 1}
-						key := s.taintAnalysis.Graph.Func.String() + "#" + strconv.Itoa(k)
-						key2 := f.String() + "#" + strconv.Itoa(0)
-						node := (*taintGraph.Nodes)[key]
-						if node.IsIntra {
-							node.Out = append(node.Out, &edge)
-							if _, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
-								continue
-							} else {
-								(*taintGraph.Edges)[key+"#"+key2] = &edge
-							}
-							if node2, ok := (*taintGraph.Nodes)[key2]; ok {
-								node2.In = append(node2.In, &edge)
-								passProperty(node2, &edge)
-							} else {
-								node2 := &Node{Canonical: signature.String(), Index: 0, Out: make([]*Edge, 0), In: make([]*Edge, 0), IsSignature: false, IsMethod: true, IsStatic: false}
-								decidePropertry(node2, ruler)
-								node2.In = append(node2.In, &edge)
-								(*taintGraph.Nodes)[f.String()] = node2
-								passProperty(node2, &edge)
-							}
-						}
-					}
-				}
-			}
+		edge := Edge{From: node.Canonical, FromIndex: node.Index, To: to, ToIndex: index, Labels: s.edgeLabels(carried, name, node), Position: s.position(inst)}
+		node2, ok := (*taintGraph.Nodes)[key2]
+		if !ok {
+			node2 = &Node{Canonical: to, Index: index, Out: make([]*Edge, 0), In: make([]*Edge, 0), IsSignature: !isMethod, IsMethod: isMethod, IsStatic: false}
+			decidePropertry(node2, ruler)
+			(*taintGraph.Nodes)[key2] = node2
+		}
+		if old, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
+			mergeEdge(old, &edge, node2)
+			continue
 		}
+		(*taintGraph.Edges)[key+"#"+key2] = &edge
+		node.Out = append(node.Out, &edge)
+		node2.In = append(node2.In, &edge)
+		passProperty(node2, &edge)
 	}
 }
 
-// collectSignatureEdges records node only use signature information
-func (s *TaintSwitcher) collectSignatureEdges(signature *types.Signature, inst *ssa.Call) {
-	ruler := s.taintAnalysis.config.Ruler
-	taintGraph := s.taintAnalysis.config.TaintGraph
-	n := signature.Params().Len()
-	for i := 0; i < n; i++ {
-		for name := range *GetTaint(s.outMap, inst.Call.Args[i].Name()) {
-			for k, v := range s.taintAnalysis.Graph.Func.Params {
-				if v.Name() == name {
-					edge := Edge{From: s.taintAnalysis.Graph.Func.String(), FromIndex: k, To: signature.String(), ToIndex: i}
-					key := s.taintAnalysis.Graph.Func.String() + "#" + strconv.Itoa(k)
-					key2 := signature.String() + "#" + strconv.Itoa(0)
-					node := (*taintGraph.Nodes)[key]
-					if node.IsIntra {
-						node.Out = append(node.Out, &edge)
-						if _, ok := (*taintGraph.Edges)[key+"#"+key2]; ok {
-							continue
-						} else {
-							(*taintGraph.Edges)[key+"#"+key2] = &edge
-						}
-						if node2, ok := (*taintGraph.Nodes)[key2]; ok {
-							node2.In = append(node2.In, &edge)
-							passProperty(node2, &edge)
-						} else {
-							node2 := &Node{Canonical: signature.String(), Index: 0, Out: make([]*Edge, 0), In: make([]*Edge, 0), IsSignature: true, IsMethod: false, IsStatic: false}
-							decidePropertry(node2, ruler)
-							node2.In = append(node2.In, &edge)
-							(*taintGraph.Nodes)[signature.String()] = node2
-							passProperty(node2, &edge)
-						}
-					}
-				}
-			}
+// edgeLabels returns labels of a taint from a node carried by a wrapper
+// they are labels emitted by the node if it is a source, else labels of the ruler, which are not removed by sanitizers
+func (s *TaintSwitcher) edgeLabels(carried *TaintWrapper, taint string, node *Node) []string {
+	universe := rule.LabelsOf(s.taintAnalysis.config.Ruler)
+	if node.IsSource {
+		universe = rule.SourceLabelsOf(s.taintAnalysis.config.Ruler, node)
+		if IsCarrier(node.Canonical) {
+			// the carrier of a source records labels of its function or field
+			universe = node.Labels
 		}
 	}
+	if s.taintAnalysis.config.ImplicitFlow {
+		// an explicit flow is also an implicit flow, so paths go on after implicit edges
+		universe = rule.Union(universe, []string{rule.Implicit})
+	}
+	return carried.Labels(taint, universe)
+}
+
+// position returns the position of an instruction which creates edges, like a call
+func (s *TaintSwitcher) position(inst ssa.Instruction) token.Position {
+	return s.taintAnalysis.Graph.Func.Prog.Fset.Position(inst.Pos())
+}
+
+// discarded is the key of results of go calls and deferred calls
+// they have no value to hold results, so results are discarded into it
+const discarded = "_"
+
+// callName returns the name of the value of a call, or discarded if the call has no value
+func callName(inst ssa.CallInstruction) string {
+	if v := inst.Value(); v != nil {
+		return v.Name()
+	}
+	return discarded
+}
+
+// mergeEdge merges labels of a new edge into an existing edge with same ends
+func mergeEdge(old *Edge, edge *Edge, node *Node) {
+	old.Labels = rule.Union(old.Labels, edge.Labels)
+	if node != nil {
+		passProperty(node, old)
+	}
 }
@@ -1,8 +1,15 @@
 package taint
 
+import (
+	"sort"
+	"strings"
+)
+
 // TaintWrapper represents a wrapper of taint
+// a taint carries all labels except labels removed by sanitizers on the way
 type TaintWrapper struct {
 	innerTaint *map[string]bool
+	removed    *map[string]map[string]bool
 }
 
 // NewTaintWrapper returns a TaintWrapper
@@ -13,13 +20,16 @@ func NewTaintWrapper(taints ...string) *TaintWrapper {
 		innnerTaint[taint] = true
 	}
 	newTaint.innerTaint = &innnerTaint
+	removed := make(map[string]map[string]bool)
+	newTaint.removed = &removed
 	return newTaint
 }
 
-// AddTaint adds taints to innerTaint
+// AddTaint adds taints with all labels to innerTaint
 func (w *TaintWrapper) AddTaint(taints ...string) {
 	for _, taint := range taints {
 		(*w.innerTaint)[taint] = true
+		delete(*w.removed, taint)
 	}
 }
 
@@ -29,16 +39,78 @@ func (w *TaintWrapper) HasTaint(taint string) bool {
 	return ok
 }
 
+// Removed returns sorted labels removed from a taint
+func (w *TaintWrapper) Removed(taint string) []string {
+	labels := make([]string, 0)
+	for label := range (*w.removed)[taint] {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Labels returns labels in universe which a taint still carries
+func (w *TaintWrapper) Labels(taint string, universe []string) []string {
+	labels := make([]string, 0)
+	for _, label := range universe {
+		if !(*w.removed)[taint][label] {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// String returns sorted taints in innerTaint, with labels removed from them
+func (w *TaintWrapper) String() string {
+	taints := make([]string, 0)
+	for taint := range *w.innerTaint {
+		if removed := w.Removed(taint); len(removed) != 0 {
+			taint += "-{" + strings.Join(removed, ", ") + "}"
+		}
+		taints = append(taints, taint)
+	}
+	sort.Strings(taints)
+	return "{" + strings.Join(taints, ", ") + "}"
+}
+
 // GetTaint returns innerTaint
 func GetTaint(flow *map[any]any, name string) *map[string]bool {
 	return GetTaintWrapper(flow, name).innerTaint
 }
 
-// InheritTaint inherits taints from a wrapper with key
-func (w *TaintWrapper) InheritTaint(flow *map[any]any, name string) {
-	oldTaint := GetTaintWrapper(flow, name)
-	for taint := range *oldTaint.innerTaint {
-		(*w.innerTaint)[taint] = true
+// InheritTaint inherits taints from a wrapper with key, and removes labels from inherited taints
+// a taint inherited more than once carries labels from any of them
+func (w *TaintWrapper) InheritTaint(flow *map[any]any, name string, removed ...string) {
+	w.inheritWrapper(GetTaintWrapper(flow, name), nil, removed...)
+}
+
+// inheritWrapper inherits taints from a wrapper like InheritTaint, taints are renamed if rename is not nil
+func (w *TaintWrapper) inheritWrapper(oldTaint *TaintWrapper, rename func(string) string, removed ...string) {
+	for oldName := range *oldTaint.innerTaint {
+		taint := oldName
+		if rename != nil {
+			taint = rename(oldName)
+		}
+		labels := make(map[string]bool)
+		for label := range (*oldTaint.removed)[oldName] {
+			labels[label] = true
+		}
+		for _, label := range removed {
+			labels[label] = true
+		}
+		if (*w.innerTaint)[taint] {
+			for label := range (*w.removed)[taint] {
+				if !labels[label] {
+					delete((*w.removed)[taint], label)
+				}
+			}
+		} else {
+			(*w.innerTaint)[taint] = true
+			(*w.removed)[taint] = labels
+		}
+		if len((*w.removed)[taint]) == 0 {
+			delete(*w.removed, taint)
+		}
 	}
 }
 
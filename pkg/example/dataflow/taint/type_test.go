@@ -0,0 +1,35 @@
+package taint
+
+import "testing"
+
+const typeSrc = `package test
+
+type Shell interface {
+	Exec(name string, arg string)
+}
+
+func SrcShell(sh Shell, a string) {
+	sh.Exec("ls", a)
+	sh.Exec("ls", a)
+}
+
+func SrcFunc(run func(string, string), a string) {
+	run("ls", a)
+}
+`
+
+func TestTypeEdges(t *testing.T) {
+	// edges to methods and functions only known by types go to the index of their args
+	c := analyze(t, typeSrc, nil)
+	assertFindings(t, c, "SrcShell#0->(Shell).Exec#0", "SrcShell#1->(Shell).Exec#2")
+	for key, edge := range *c.TaintGraph.Edges {
+		if node := edgeTarget(c.TaintGraph, edge); node == nil || node.Index != edge.ToIndex {
+			t.Errorf("edge %s goes to node %v, want index %d", key, node, edge.ToIndex)
+		}
+	}
+	for key, node := range *c.TaintGraph.Nodes {
+		if len(node.Out) > 1 || len(node.In) > 1 {
+			t.Errorf("node %s has %d out and %d in edges, want one edge of each call", key, len(node.Out), len(node.In))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package verybusyexpression
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/graph"
+	"github.com/cokeBeer/goot/pkg/dataflow/toolkits/scalar"
+	"github.com/cokeBeer/goot/pkg/dataflow/util/entry"
+	"github.com/cokeBeer/goot/pkg/example/dataflow/expression"
+	"github.com/dnote/color"
+	"golang.org/x/tools/go/ssa"
+)
+
+// VeryBusyExpressionAnalysis represents a very busy expressions analysis
+// it is a backward must analysis, an expression is very busy at a point if it is computed on every path from the point
+// before any of its operands is redefined
+type VeryBusyExpressionAnalysis struct {
+	scalar.BaseFlowAnalysis
+	expressions map[string]*expression.Expression
+	Hoistings   []*Hoisting
+	GraphDstDir string
+}
+
+// Hoisting represents a branch before which very busy expressions can be computed once
+type Hoisting struct {
+	Instruction string
+	Block       int
+	Expressions []string
+}
+
+// New creates a VeryBusyExpressionAnalysis
+func New(g *graph.UnitGraph) *VeryBusyExpressionAnalysis {
+	veryBusyExpressionAnalysis := new(VeryBusyExpressionAnalysis)
+	veryBusyExpressionAnalysis.BaseFlowAnalysis = *scalar.NewBase(g)
+	veryBusyExpressionAnalysis.expressions = expression.Collect(g.Func)
+	veryBusyExpressionAnalysis.Hoistings = make([]*Hoisting, 0)
+	return veryBusyExpressionAnalysis
+}
+
+// IsForward returns whether this analysis is a forward flow analysis
+func (a *VeryBusyExpressionAnalysis) IsForward() bool {
+	return false
+}
+
+// NewInitalFlow returns a new flow
+// the top of a must analysis is the set of all expressions
+func (a *VeryBusyExpressionAnalysis) NewInitalFlow() *map[any]any {
+	m := make(map[any]any)
+	for k := range a.expressions {
+		m[k] = true
+	}
+	return &m
+}
+
+// EntryInitalFlow returns a new flow for entry
+// no expression is very busy at exits of the function
+func (a *VeryBusyExpressionAnalysis) EntryInitalFlow() *map[any]any {
+	m := make(map[any]any)
+	return &m
+}
+
+// Copy copy from srcMap to dstMap
+// dstMap is cleared first because a must analysis removes facts
+func (a *VeryBusyExpressionAnalysis) Copy(srcMap *map[any]any, dstMap *map[any]any) {
+	if srcMap == dstMap {
+		return
+	}
+	for k := range *dstMap {
+		delete(*dstMap, k)
+	}
+	for k, v := range *srcMap {
+		(*dstMap)[k] = v
+	}
+}
+
+// MergeInto merge from in to inout based on unit
+// an expression is very busy only if it is very busy on all successors
+func (a *VeryBusyExpressionAnalysis) MergeInto(unit ssa.Instruction, inout *map[any]any, in *map[any]any) {
+	for k := range *inout {
+		if _, ok := (*in)[k]; !ok {
+			delete(*inout, k)
+		}
+	}
+}
+
+// FlowThrougth calculate outMap based on inMap and unit
+// in a backward analysis, inMap is the flow after unit and outMap is the flow before unit
+func (a *VeryBusyExpressionAnalysis) FlowThrougth(inMap *map[any]any, unit ssa.Instruction, outMap *map[any]any) {
+	a.Copy(inMap, outMap)
+	for k, e := range a.expressions {
+		if expression.Kills(unit, e) {
+			delete(*outMap, k)
+		}
+	}
+	if e := expression.Of(unit); e != nil {
+		(*outMap)[e.Key] = true
+	}
+}
+
+// End handle result of analysis
+// expressions very busy before a branch can be hoisted above it
+func (a *VeryBusyExpressionAnalysis) End(universe []*entry.Entry) {
+	if a.GraphDstDir != "" {
+		err := graph.NewExporter(a.Graph, universe).Persist(a.GraphDstDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+	for _, v := range universe {
+		color.Set(color.FgGreen)
+		fmt.Println("very busy expressions before instruction: " + v.Data.String())
+		color.Unset()
+		keys := expression.Keys(v.OutFlow)
+		fmt.Println("{" + strings.Join(keys, ", ") + "}")
+		fmt.Println()
+		if _, ok := v.Data.(*ssa.If); ok && len(keys) != 0 {
+			a.Hoistings = append(a.Hoistings, &Hoisting{Instruction: graph.InstructionString(v.Data),
+				Block: v.Data.Block().Index, Expressions: keys})
+		}
+	}
+	for _, h := range a.Hoistings {
+		color.Set(color.FgYellow)
+		fmt.Printf("hoisting candidate: %s can be computed before %s in block %d\n",
+			strings.Join(h.Expressions, ", "), h.Instruction, h.Block)
+		color.Unset()
+	}
+}